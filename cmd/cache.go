@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+func cacheCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "manage the Redis job cache",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "flush",
+				Usage: "drop every cached job, list and search page",
+				Action: withStores(func(c *cli.Context, s *stores) error {
+					if err := s.cache.InvalidateAll(c.Context); err != nil {
+						return err
+					}
+					fmt.Fprintln(c.App.Writer, "job cache flushed")
+					return nil
+				}),
+			},
+			{
+				Name:  "stats",
+				Usage: "count cached keys and show Redis hit rates",
+				Action: withStores(func(c *cli.Context, s *stores) error {
+					counts, err := s.cache.Stats(c.Context)
+					if err != nil {
+						return err
+					}
+					families := make([]string, 0, len(counts))
+					for family := range counts {
+						families = append(families, family)
+					}
+					sort.Strings(families)
+					for _, family := range families {
+						fmt.Fprintf(c.App.Writer, "keys.%s: %d\n", family, counts[family])
+					}
+					for _, section := range []string{"stats", "memory"} {
+						info, err := s.redis.Info(c.Context, section)
+						if err != nil {
+							return err
+						}
+						fields := parseInfo(info)
+						for _, key := range []string{"keyspace_hits", "keyspace_misses", "evicted_keys", "used_memory_human"} {
+							if value, ok := fields[key]; ok {
+								fmt.Fprintf(c.App.Writer, "redis.%s: %s\n", key, value)
+							}
+						}
+					}
+					return nil
+				}),
+			},
+			{
+				Name:  "warm",
+				Usage: "cache the first pages of active jobs and the jobs on them",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "pages", Value: 5, Usage: "number of list pages to warm"},
+				},
+				Action: withStores(func(c *cli.Context, s *stores) error {
+					pages, jobsWarmed, err := warmCache(c, s, c.Int("pages"))
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(c.App.Writer, "warmed %d list pages and %d jobs\n", pages, jobsWarmed)
+					return nil
+				}),
+			},
+		},
+	}
+}
+
+// warmCache caches the first pages of the public job list, at the default
+// page size, and every job on them.
+func warmCache(c *cli.Context, s *stores, pages int) (int, int, error) {
+	limit := s.cfg.Pagination.DefaultLimit
+	warmed, jobsWarmed := 0, 0
+	for page := 0; page < pages; page++ {
+		list, total, err := s.jobs.List(c.Context, false, page*limit, limit)
+		if err != nil {
+			return warmed, jobsWarmed, err
+		}
+		warmed++
+		for i := range list {
+			if err := s.cache.SetJob(c.Context, &list[i]); err != nil {
+				return warmed, jobsWarmed, err
+			}
+			jobsWarmed++
+		}
+		if int64((page+1)*limit) >= total {
+			break
+		}
+	}
+	return warmed, jobsWarmed, nil
+}
+
+// parseInfo splits the "key:value" lines of Redis' INFO output.
+func parseInfo(info string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(info, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && !strings.HasPrefix(key, "#") {
+			fields[key] = value
+		}
+	}
+	return fields
+}
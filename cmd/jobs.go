@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/config"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/logging"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+)
+
+// stores are the connections a maintenance command works with. Changes go
+// through the job repository, so they emit the same events and cache
+// invalidations as the API.
+type stores struct {
+	cfg   *config.Config
+	db    *gorm.DB
+	redis *db.RedisClient
+	cache *jobs.JobCache
+	jobs  jobs.JobRepository
+}
+
+func (s *stores) close() {
+	s.redis.Close()
+	db.Close(s.db)
+}
+
+// withStores loads the configuration and connects to MySQL and Redis
+// before running action. Logs go to stderr so that they do not mix with
+// the command's output.
+func withStores(action func(*cli.Context, *stores) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		cfg, err := loadConfig(c)
+		if err != nil {
+			return err
+		}
+		if _, err := logging.Setup(os.Stderr, cfg.Logging.Level, "text"); err != nil {
+			return err
+		}
+		dbConn := connectDB(cfg)
+		if err := checkMigrations(c.Context, dbConn, "check"); err != nil {
+			db.Close(dbConn)
+			return err
+		}
+		redisClient := connectRedis(cfg)
+		cache := jobs.NewJobCache(redisClient, cacheTTLs(cfg), stampedeOptions(cfg))
+		s := &stores{
+			cfg:   cfg,
+			db:    dbConn,
+			redis: redisClient,
+			cache: cache,
+			jobs:  jobs.NewGormJobRepository(dbConn, cache),
+		}
+		defer s.close()
+		return action(c, s)
+	}
+}
+
+func jobsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "jobs",
+		Usage: "inspect and maintain jobs in the database",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "get",
+				Usage:     "print a job as JSON",
+				ArgsUsage: "<id>",
+				Before:    requireJobIDs(1),
+				Action: withStores(func(c *cli.Context, s *stores) error {
+					ids, _ := jobIDs(c, 1)
+					var job jobs.Job
+					if err := s.db.WithContext(c.Context).First(&job, ids[0]).Error; err != nil {
+						return notFound(err, ids[0])
+					}
+					enc := json.NewEncoder(c.App.Writer)
+					enc.SetIndent("", "  ")
+					return enc.Encode(job)
+				}),
+			},
+			{
+				Name:  "list",
+				Usage: "list jobs, newest first",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "status", Value: "all", Usage: "active, inactive or all"},
+					&cli.StringFlag{Name: "company", Usage: "only jobs of this company"},
+					&cli.IntFlag{Name: "limit", Value: 20},
+					&cli.IntFlag{Name: "offset"},
+				},
+				Before: func(c *cli.Context) error {
+					switch c.String("status") {
+					case "active", "inactive", "all":
+						return nil
+					}
+					return fmt.Errorf("--status must be active, inactive or all, got %q", c.String("status"))
+				},
+				Action: withStores(func(c *cli.Context, s *stores) error {
+					q := s.db.WithContext(c.Context).Model(&jobs.Job{})
+					switch c.String("status") {
+					case "active":
+						q = q.Where("status = ?", true)
+					case "inactive":
+						q = q.Where("status = ?", false)
+					}
+					if company := c.String("company"); company != "" {
+						q = q.Where("company = ?", company)
+					}
+					var total int64
+					if err := q.Count(&total).Error; err != nil {
+						return err
+					}
+					var list []jobs.Job
+					err := q.Order("created_at desc").Offset(c.Int("offset")).Limit(c.Int("limit")).Find(&list).Error
+					if err != nil {
+						return err
+					}
+					tw := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(tw, "ID\tTITLE\tCOMPANY\tCITY\tACTIVE\tCREATED AT")
+					for _, job := range list {
+						fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%t\t%s\n", job.ID, job.Title, job.Company, job.City, job.Status,
+							time.Unix(job.CreatedAt, 0).UTC().Format(time.RFC3339))
+					}
+					if err := tw.Flush(); err != nil {
+						return err
+					}
+					fmt.Fprintf(c.App.Writer, "%d of %d jobs\n", len(list), total)
+					return nil
+				}),
+			},
+			{
+				Name:      "close",
+				Usage:     "mark jobs inactive",
+				ArgsUsage: "<id>...",
+				Before:    requireJobIDs(-1),
+				Action: withStores(func(c *cli.Context, s *stores) error {
+					ids, _ := jobIDs(c, -1)
+					for _, id := range ids {
+						if err := s.jobs.Update(c.Context, id, map[string]interface{}{"status": false}); err != nil {
+							return notFound(err, id)
+						}
+						fmt.Fprintf(c.App.Writer, "closed job %d\n", id)
+					}
+					return nil
+				}),
+			},
+			{
+				Name:  "purge",
+				Usage: "delete inactive jobs created before a cutoff",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "older-than-days", Value: 90, Usage: "only jobs created at least this many days ago"},
+					&cli.BoolFlag{Name: "dry-run", Usage: "list the jobs without deleting them"},
+				},
+				Before: func(c *cli.Context) error {
+					if c.Int("older-than-days") < 0 {
+						return errors.New("--older-than-days must not be negative")
+					}
+					return nil
+				},
+				Action: withStores(func(c *cli.Context, s *stores) error {
+					cutoff := time.Now().AddDate(0, 0, -c.Int("older-than-days")).Unix()
+					var ids []uint
+					err := s.db.WithContext(c.Context).Model(&jobs.Job{}).
+						Where("status = ? AND created_at < ?", false, cutoff).
+						Order("id").
+						Pluck("id", &ids).Error
+					if err != nil {
+						return err
+					}
+					if c.Bool("dry-run") {
+						for _, id := range ids {
+							fmt.Fprintf(c.App.Writer, "would purge job %d\n", id)
+						}
+						fmt.Fprintf(c.App.Writer, "%d jobs would be purged\n", len(ids))
+						return nil
+					}
+					for i, id := range ids {
+						if err := s.jobs.Delete(c.Context, id); err != nil {
+							return fmt.Errorf("purged %d of %d jobs: %w", i, len(ids), err)
+						}
+					}
+					fmt.Fprintf(c.App.Writer, "purged %d jobs\n", len(ids))
+					return nil
+				}),
+			},
+		},
+	}
+}
+
+func requireJobIDs(n int) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		_, err := jobIDs(c, n)
+		return err
+	}
+}
+
+// jobIDs parses the job IDs given as arguments; n is the exact number
+// expected, or -1 for one or more.
+func jobIDs(c *cli.Context, n int) ([]uint, error) {
+	args := c.Args().Slice()
+	if (n < 0 && len(args) == 0) || (n >= 0 && len(args) != n) {
+		return nil, fmt.Errorf("usage: %s %s", c.Command.HelpName, c.Command.ArgsUsage)
+	}
+	ids := make([]uint, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 32)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid job ID %q", arg)
+		}
+		ids[i] = uint(id)
+	}
+	return ids, nil
+}
+
+func notFound(err error, id uint) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("job %d not found", id)
+	}
+	return err
+}
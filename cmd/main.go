@@ -6,37 +6,123 @@
 package main
 
 import (
-	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/AtaAksoy/se4458-go-job-posting-service/config"
 	_ "github.com/AtaAksoy/se4458-go-job-posting-service/docs"
-	"github.com/AtaAksoy/se4458-go-job-posting-service/internal"
 	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
-	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
 )
 
 func main() {
-	cfg := config.LoadConfig()
+	if err := newApp().Run(os.Args); err != nil {
+		exit(err)
+	}
+}
 
-	dbConn := db.Connect(cfg.DBDSN, &jobs.Job{})
+func newApp() *cli.App {
+	return &cli.App{
+		Name:  "main",
+		Usage: "job posting service; runs the server when no command is given",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Usage:   "path to a YAML or TOML config file",
+				EnvVars: []string{"CONFIG_FILE"},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() > 0 {
+				return fmt.Errorf("unknown command %q, see --help", c.Args().First())
+			}
+			return serveAction(c)
+		},
+		Commands: []*cli.Command{
+			{
+				Name:   "serve",
+				Usage:  "run the HTTP server and background workers",
+				Action: serveAction,
+			},
+			migrateCommand(),
+			seedCommand(),
+			cacheCommand(),
+			jobsCommand(),
+			{
+				Name:  "config",
+				Usage: "inspect the configuration",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "print",
+						Usage: "print the effective configuration with secrets masked",
+						Action: func(c *cli.Context) error {
+							cfg, err := config.Load(c.String("config"))
+							if err != nil {
+								return err
+							}
+							if err := cfg.Print(c.App.Writer); err != nil {
+								return err
+							}
+							return cfg.Validate()
+						},
+					},
+				},
+			},
+		},
+	}
+}
 
-	redisClient := db.NewRedisClient(cfg.RedisAddr, cfg.RedisPass, cfg.RedisDB)
+func serveAction(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+	return serve(cfg, c.String("config"))
+}
 
-	ctx := context.Background()
-	if err := redisClient.Ping(ctx); err != nil {
-		log.Printf("Warning: Redis connection failed: %v", err)
-	} else {
-		log.Println("Redis connected successfully")
+// loadConfig loads and validates the configuration named by --config.
+func loadConfig(c *cli.Context) (*config.Config, error) {
+	cfg, err := config.Load(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
+	return cfg, nil
+}
+
+func connectDB(cfg *config.Config) *gorm.DB {
+	return db.Connect(cfg.Database.DSN, db.PoolOptions{
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(cfg.Database.ConnMaxLifetimeMinutes) * time.Minute,
+		ConnMaxIdleTime: time.Duration(cfg.Database.ConnMaxIdleTimeMinutes) * time.Minute,
+	})
+}
 
-	jobCache := jobs.NewJobCache(redisClient)
+func connectRedis(cfg *config.Config) *db.RedisClient {
+	return db.NewRedisClient(db.RedisOptions{
+		Addr:        cfg.Redis.Addr,
+		Password:    cfg.Redis.Password,
+		DB:          cfg.Redis.DB,
+		PoolSize:    cfg.Redis.PoolSize,
+		DialTimeout: time.Duration(cfg.Redis.DialTimeoutSeconds) * time.Second,
+		ReadTimeout: time.Duration(cfg.Redis.ReadTimeoutSeconds) * time.Second,
+	})
+}
 
-	repo := jobs.NewGormJobRepository(dbConn, jobCache)
-	handler := jobs.NewJobHandler(repo)
+// exit reports an error from a command. Configuration errors can span
+// several lines, which a log line would escape.
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
 
-	r := internal.SetupRouter(handler)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("failed to run server: %v", err)
-	}
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db/migrations"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+)
+
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "apply, revert or list the database migrations",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "apply all pending migrations",
+				Action: withMigrator(func(c *cli.Context, migrator *migrations.Migrator) error {
+					applied, err := migrator.Up(c.Context)
+					report(c.App.Writer, "applied", applied)
+					return err
+				}),
+			},
+			{
+				Name:      "down",
+				Usage:     "revert the last applied migrations",
+				ArgsUsage: "[steps]",
+				Before: func(c *cli.Context) error {
+					_, err := downSteps(c)
+					return err
+				},
+				Action: withMigrator(func(c *cli.Context, migrator *migrations.Migrator) error {
+					steps, _ := downSteps(c)
+					reverted, err := migrator.Down(c.Context, steps)
+					report(c.App.Writer, "reverted", reverted)
+					return err
+				}),
+			},
+			{
+				Name:  "status",
+				Usage: "list every migration and whether it is applied",
+				Action: withMigrator(func(c *cli.Context, migrator *migrations.Migrator) error {
+					statuses, err := migrator.Status(c.Context)
+					if err != nil {
+						return err
+					}
+					tw := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+					for _, status := range statuses {
+						appliedAt := "pending"
+						if status.AppliedAt != nil {
+							appliedAt = time.Unix(*status.AppliedAt, 0).UTC().Format(time.RFC3339)
+						}
+						fmt.Fprintf(tw, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+					}
+					return tw.Flush()
+				}),
+			},
+		},
+	}
+}
+
+// downSteps parses the optional number of migrations to revert.
+func downSteps(c *cli.Context) (int, error) {
+	if c.NArg() == 0 {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(c.Args().First())
+	if err != nil || n < 1 || c.NArg() > 1 {
+		return 0, fmt.Errorf("migrate down: steps must be a positive number, got %q", strings.Join(c.Args().Slice(), " "))
+	}
+	return n, nil
+}
+
+func withMigrator(action func(*cli.Context, *migrations.Migrator) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		cfg, err := loadConfig(c)
+		if err != nil {
+			return err
+		}
+		dbConn := db.Connect(cfg.Database.DSN, db.PoolOptions{MaxOpenConns: 2})
+		defer db.Close(dbConn)
+		migrator, err := migrations.New(dbConn)
+		if err != nil {
+			return err
+		}
+		return action(c, migrator)
+	}
+}
+
+func report(w io.Writer, verb string, done []migrations.Migration) {
+	if len(done) == 0 {
+		fmt.Fprintf(w, "no migrations %s\n", verb)
+		return
+	}
+	for _, migration := range done {
+		fmt.Fprintf(w, "%s %04d_%s\n", verb, migration.Version, migration.Name)
+	}
+}
+
+// checkMigrations handles pending migrations at startup according to mode,
+// which is database.migrations: "check", "up" or "ignore".
+func checkMigrations(ctx context.Context, dbConn *gorm.DB, mode string) error {
+	migrator, err := migrations.New(dbConn)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(mode) {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			slog.Info("applied migration", "version", migration.Version, "name", migration.Name)
+		}
+		return err
+	case "ignore":
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			slog.Warn("starting with pending migrations", "pending", len(pending), "next", pending[0].Name)
+		}
+		return nil
+	default:
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending, starting with %04d_%s; run \"migrate up\" or set DB_MIGRATIONS=up",
+				len(pending), pending[0].Version, pending[0].Name)
+		}
+		return nil
+	}
+}
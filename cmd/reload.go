@@ -0,0 +1,82 @@
+package main
+
+import (
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/config"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/logging"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/ratelimit"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+)
+
+// reloader applies configuration reloads to the subsystems that can change
+// settings while serving. It is only used from the config.Watch goroutine.
+type reloader struct {
+	path   string
+	cfg    *config.Config
+	rules  *ratelimit.Rules
+	cache  *jobs.JobCache
+	limits *ratelimit.Settings
+}
+
+func (r *reloader) reload() {
+	next, changes, err := r.cfg.Reload(r.path)
+	if err != nil {
+		slog.Error("config: reload failed, keeping the current configuration", "error", err)
+		return
+	}
+	// The rules file is read again even when its path is unchanged.
+	rules, err := ratelimit.LoadRules(next.RateLimit.File)
+	if err != nil {
+		slog.Error("config: reload failed, keeping the current configuration", "error", err)
+		return
+	}
+
+	var applied []string
+	for _, ch := range changes {
+		if ch.Reloadable {
+			applied = append(applied, ch.String())
+		} else {
+			slog.Warn("config: setting requires a restart, change ignored", "key", ch.Key, "current", ch.Old, "new", ch.New)
+		}
+	}
+	if !reflect.DeepEqual(rules, r.rules) {
+		applied = append(applied, "rate limit rules updated")
+	}
+
+	if err := logging.SetLevel(next.Logging.Level); err != nil {
+		// Validate accepted it, so this cannot happen.
+		slog.Error("config: failed to set log level", "error", err)
+	}
+	metrics.SetEnabled(next.Features.Metrics)
+	r.cache.SetTTLs(cacheTTLs(next))
+	r.cache.SetStampede(stampedeOptions(next))
+	r.limits.Set(next.Features.RateLimit, rules)
+	r.cfg, r.rules = next, rules
+
+	if len(applied) == 0 {
+		slog.Info("config: reloaded, nothing changed")
+		return
+	}
+	slog.Info("config: reloaded", "changes", applied)
+}
+
+func cacheTTLs(cfg *config.Config) jobs.CacheTTLs {
+	return jobs.CacheTTLs{
+		Job:    time.Duration(cfg.Cache.JobTTLMinutes) * time.Minute,
+		List:   time.Duration(cfg.Cache.ListTTLMinutes) * time.Minute,
+		Search: time.Duration(cfg.Cache.SearchTTLMinutes) * time.Minute,
+	}
+}
+
+func stampedeOptions(cfg *config.Config) jobs.StampedeOptions {
+	return jobs.StampedeOptions{
+		Lock:         cfg.Cache.Lock,
+		LockTTL:      time.Duration(cfg.Cache.LockTTLMs) * time.Millisecond,
+		LockWait:     time.Duration(cfg.Cache.LockWaitMs) * time.Millisecond,
+		EarlyRefresh: cfg.Cache.EarlyRefresh,
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/seed"
+	"github.com/urfave/cli/v2"
+)
+
+func seedCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "seed",
+		Usage: "insert generated jobs; the same options always generate the same jobs",
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "count", Value: 1000, Usage: "number of jobs to insert"},
+			&cli.Uint64Flag{Name: "seed", Value: 1, Usage: "random seed"},
+			// A fixed default keeps runs without --end reproducible.
+			&cli.StringFlag{Name: "end", Value: "2025-06-30", Usage: "newest creation date, YYYY-MM-DD (UTC)"},
+			&cli.IntFlag{Name: "months", Value: 6, Usage: "spread creation dates over this many months before --end"},
+			&cli.Float64Flag{Name: "inactive-ratio", Value: 0.15, Usage: "share of closed jobs"},
+			&cli.IntFlag{Name: "batch-size", Value: 500, Usage: "rows per INSERT"},
+			&cli.BoolFlag{Name: "warm-cache", Usage: "warm the job cache afterwards, like \"cache warm\""},
+		},
+		Before: func(c *cli.Context) error {
+			_, err := seedOptions(c)
+			return err
+		},
+		Action: withStores(func(c *cli.Context, s *stores) error {
+			opts, _ := seedOptions(c)
+			count := c.Int("count")
+			err := seed.Insert(c.Context, s.db, seed.NewGenerator(opts), count, c.Int("batch-size"), func(inserted int) {
+				fmt.Fprintf(c.App.ErrWriter, "\rinserted %d/%d jobs", inserted, count)
+			})
+			fmt.Fprintln(c.App.ErrWriter)
+			if err != nil {
+				return err
+			}
+			// Cached list and search pages no longer match the table.
+			if err := s.cache.InvalidateAll(c.Context); err != nil {
+				return err
+			}
+			fmt.Fprintf(c.App.Writer, "inserted %d jobs (seed %d)\n", count, opts.Seed)
+			if c.Bool("warm-cache") {
+				pages, jobsWarmed, err := warmCache(c, s, 5)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(c.App.Writer, "warmed %d list pages and %d jobs\n", pages, jobsWarmed)
+			}
+			return nil
+		}),
+	}
+}
+
+func seedOptions(c *cli.Context) (seed.Options, error) {
+	end, err := time.Parse(time.DateOnly, c.String("end"))
+	if err != nil {
+		return seed.Options{}, fmt.Errorf("--end must be a date like 2025-01-31, got %q", c.String("end"))
+	}
+	switch {
+	case c.Int("count") < 1:
+		return seed.Options{}, errors.New("--count must be positive")
+	case c.Int("batch-size") < 1:
+		return seed.Options{}, errors.New("--batch-size must be positive")
+	case c.Int("months") < 1:
+		return seed.Options{}, errors.New("--months must be positive")
+	case c.Float64("inactive-ratio") < 0 || c.Float64("inactive-ratio") > 1:
+		return seed.Options{}, errors.New("--inactive-ratio must be between 0 and 1")
+	}
+	return seed.Options{
+		Seed:          c.Uint64("seed"),
+		End:           end,
+		Months:        c.Int("months"),
+		InactiveRatio: c.Float64("inactive-ratio"),
+	}, nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/config"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/health"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/logging"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/ratelimit"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/tracing"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/alerts"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/apikeys"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/applications"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/outbox"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/pagination"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/users"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// serve runs the HTTP server and the background workers until SIGINT or
+// SIGTERM, then shuts them down in order.
+func serve(cfg *config.Config, configPath string) error {
+	if _, err := logging.Setup(os.Stdout, cfg.Logging.Level, cfg.Logging.Format); err != nil {
+		fatal("failed to configure logging", err)
+	}
+	if os.Getenv(gin.EnvGinMode) == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter)
+	if err != nil {
+		fatal("failed to configure tracing", err)
+	}
+
+	dbConn := connectDB(cfg)
+	if err := checkMigrations(context.Background(), dbConn, cfg.Database.Migrations); err != nil {
+		fatal("database schema is not ready", err)
+	}
+
+	redisClient := connectRedis(cfg)
+
+	ctx := context.Background()
+	if err := redisClient.Ping(ctx); err != nil {
+		slog.Warn("Redis connection failed", "error", err)
+	} else {
+		slog.Info("Redis connected successfully")
+	}
+
+	jobCache := jobs.NewJobCache(redisClient, cacheTTLs(cfg), stampedeOptions(cfg))
+	pages := pagination.Limits{Default: cfg.Pagination.DefaultLimit, Max: cfg.Pagination.MaxLimit}
+
+	notifier, err := alerts.NewNotifier(alerts.NotifierConfig{
+		Kind:         cfg.Alerts.Notifier,
+		FilePath:     cfg.Alerts.FilePath,
+		WebhookURL:   cfg.Alerts.WebhookURL,
+		SMTPAddr:     cfg.Alerts.SMTPAddr,
+		SMTPUsername: cfg.Alerts.SMTPUsername,
+		SMTPPassword: cfg.Alerts.SMTPPassword,
+		SMTPFrom:     cfg.Alerts.SMTPFrom,
+	})
+	if err != nil {
+		fatal("failed to configure alert notifier", err)
+	}
+	// The relay feeds the alerter and the dispatcher, so it gets its own
+	// context and is stopped before them on shutdown.
+	var relayDone, workersDone sync.WaitGroup
+	relayCtx, stopRelay := context.WithCancel(ctx)
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+
+	savedSearchRepo := alerts.NewGormSavedSearchRepository(dbConn)
+	alerter := alerts.NewAlerter(savedSearchRepo, notifier)
+	runWorker(&workersDone, workersCtx, alerter.Run)
+
+	webhookRepo := webhooks.NewGormWebhookRepository(dbConn)
+	dispatcher := webhooks.NewDispatcher(webhookRepo)
+	runWorker(&workersDone, workersCtx, dispatcher.Run)
+
+	publishers := jobs.Publishers{alerter, dispatcher}
+	if cfg.Events.Stream != "" {
+		publishers = append(publishers, jobs.NewStreamPublisher(redisClient, cfg.Events.Stream, cfg.Events.StreamMaxLen))
+	}
+	relay := outbox.NewRelay(dbConn, jobs.OutboxHandler(publishers))
+	runWorker(&relayDone, relayCtx, relay.Run)
+
+	repo := jobs.NewGormJobRepository(dbConn, jobCache)
+	handler := jobs.NewJobHandler(repo, pages, jobs.ContentRules{
+		TitleMaxLength:       cfg.Jobs.TitleMaxLength,
+		DescriptionMaxLength: cfg.Jobs.DescriptionMaxLength,
+		CompanyMaxLength:     cfg.Jobs.CompanyMaxLength,
+		CityMaxLength:        cfg.Jobs.CityMaxLength,
+		DefaultLanguage:      cfg.Jobs.DefaultLanguage,
+	})
+
+	applicationRepo := applications.NewGormApplicationRepository(dbConn)
+	applicationHandler := applications.NewApplicationHandler(applicationRepo, repo, pages)
+	savedSearchHandler := alerts.NewSavedSearchHandler(savedSearchRepo)
+	webhookHandler := webhooks.NewWebhookHandler(webhookRepo, pages)
+
+	apiKeyRepo := apikeys.NewGormAPIKeyRepository(dbConn)
+	apiKeyService := apikeys.NewService(apiKeyRepo)
+	apiKeyHandler := apikeys.NewAPIKeyHandler(apiKeyRepo, apiKeyService)
+
+	keyConfig := auth.KeyConfig{
+		HMACSecret:    cfg.Auth.HMACSecret,
+		PublicKeyFile: cfg.Auth.PublicKeyFile,
+		JWKSFile:      cfg.Auth.JWKSFile,
+		Issuer:        cfg.Auth.Issuer,
+		Audience:      cfg.Auth.Audience,
+	}
+	var verifier *auth.Verifier
+	var policy *auth.Policy
+	var authHandler *users.AuthHandler
+	sessions := users.NewSessionStore(redisClient, time.Duration(cfg.Auth.RefreshTokenTTLHours)*time.Hour)
+	if cfg.Auth.Disabled {
+		slog.Warn("authentication is disabled, write endpoints are open")
+	} else {
+		verifier, err = auth.NewVerifier(keyConfig)
+		if err != nil {
+			fatal("failed to configure authentication (set AUTH_DISABLED=true to run without it)", err)
+		}
+		policy, err = auth.LoadPolicy(cfg.Auth.RBACPolicyFile)
+		if err != nil {
+			fatal("failed to load RBAC policy", err)
+		}
+
+		signer, err := auth.NewSigner(keyConfig, time.Duration(cfg.Auth.AccessTokenTTLMinutes)*time.Minute)
+		if err != nil {
+			slog.Warn("account login is disabled", "error", err)
+		} else {
+			userRepo := users.NewGormUserRepository(dbConn)
+			throttle := users.NewLoginThrottle(redisClient, cfg.Auth.LoginMaxAttemptsPerAccount, cfg.Auth.LoginMaxAttemptsPerIP,
+				time.Duration(cfg.Auth.LoginThrottleWindowMinutes)*time.Minute)
+			authHandler = users.NewAuthHandler(users.NewService(userRepo, sessions, throttle, signer))
+		}
+	}
+
+	// The limiter is built even when rate limiting is off, so that a reload
+	// can switch it on.
+	rules, err := ratelimit.LoadRules(cfg.RateLimit.File)
+	if err != nil {
+		fatal("failed to load rate limits", err)
+	}
+	limits := ratelimit.NewSettings(cfg.Features.RateLimit, rules)
+	limiter := ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redisClient), ratelimit.NewMemoryLimiter())
+	metrics.SetEnabled(cfg.Features.Metrics)
+
+	healthHandler := health.NewHandler(time.Duration(cfg.Health.CheckTimeoutMs)*time.Millisecond,
+		health.Check{Name: "mysql", Critical: true, Ping: func(ctx context.Context) error { return db.Ping(ctx, dbConn) }},
+		health.Check{Name: "redis", Critical: cfg.Health.ReadyRequireCache, Ping: redisClient.Ping},
+	)
+
+	r, err := internal.SetupRouter(internal.Dependencies{
+		JobHandler:         handler,
+		ApplicationHandler: applicationHandler,
+		SavedSearchHandler: savedSearchHandler,
+		WebhookHandler:     webhookHandler,
+		APIKeyHandler:      apiKeyHandler,
+		Verifier:           verifier,
+		APIKeys:            apiKeyService,
+		AuthHandler:        authHandler,
+		Sessions:           sessions,
+		ProtectReads:       cfg.Auth.ProtectReads,
+		Policy:             policy,
+		RateLimiter:        limiter,
+		RateLimits:         limits,
+		Health:             healthHandler,
+		TrustedProxies:     cfg.Server.TrustedProxyList(),
+	})
+	if err != nil {
+		fatal("failed to configure trusted proxies", err)
+	}
+	srv := &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      r,
+		ReadTimeout:  time.Duration(cfg.Server.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout: time.Duration(cfg.Server.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:  time.Duration(cfg.Server.IdleTimeoutSeconds) * time.Second,
+		ErrorLog:     slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+	signalCtx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	// SIGHUP is handled from here on, so it cannot terminate the process
+	// once the server is up.
+	reload := &reloader{path: configPath, cfg: cfg, rules: rules, cache: jobCache, limits: limits}
+	if err := config.Watch(signalCtx, []string{configPath, cfg.RateLimit.File}, reload.reload); err != nil {
+		slog.Error("config: live reload is disabled", "error", err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "addr", srv.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-serveErr:
+		fatal("failed to run server", err)
+	case <-signalCtx.Done():
+	}
+	stopSignals()
+
+	// Fail readiness first and give load balancers time to notice before
+	// the listener closes.
+	slog.Info("shutting down", "timeout_seconds", cfg.Server.ShutdownTimeoutSeconds)
+	healthHandler.Drain()
+	time.Sleep(time.Duration(cfg.Server.ShutdownDrainDelaySeconds) * time.Second)
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Server.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not drain in time", "error", err)
+	}
+
+	stopRelay()
+	if !wait(shutdownCtx, &relayDone) {
+		slog.Error("outbox relay did not stop in time")
+	}
+	stopWorkers()
+	if !wait(shutdownCtx, &workersDone) {
+		slog.Error("background workers did not stop in time")
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	if err := redisClient.Close(); err != nil {
+		slog.Error("failed to close Redis client", "error", err)
+	}
+	if err := db.Close(dbConn); err != nil {
+		slog.Error("failed to close database", "error", err)
+	}
+	slog.Info("shutdown complete")
+	return nil
+}
+
+func runWorker(wg *sync.WaitGroup, ctx context.Context, run func(context.Context)) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		run(ctx)
+	}()
+}
+
+// wait waits for wg until ctx is done and reports whether it finished.
+func wait(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
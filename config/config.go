@@ -1,51 +1,302 @@
 package config
 
 import (
-	"log"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"strconv"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
+// Config is the service configuration. Values come from the built-in
+// defaults, then the optional config file, then environment variables
+// (named by the env tags), each overriding the previous one. Fields tagged
+// reload can change while running; see Reload.
 type Config struct {
-	DBDSN     string
-	Port      string
-	RedisAddr string
-	RedisDB   int
-	RedisPass string
+	Server     ServerConfig     `yaml:"server" toml:"server"`
+	Database   DatabaseConfig   `yaml:"database" toml:"database"`
+	Redis      RedisConfig      `yaml:"redis" toml:"redis"`
+	Cache      CacheConfig      `yaml:"cache" toml:"cache"`
+	Pagination PaginationConfig `yaml:"pagination" toml:"pagination"`
+	Jobs       JobsConfig       `yaml:"jobs" toml:"jobs"`
+	Features   FeaturesConfig   `yaml:"features" toml:"features"`
+	Auth       AuthConfig       `yaml:"auth" toml:"auth"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit" toml:"rate_limit"`
+	Alerts     AlertsConfig     `yaml:"alerts" toml:"alerts"`
+	Events     EventsConfig     `yaml:"events" toml:"events"`
+	Logging    LoggingConfig    `yaml:"logging" toml:"logging"`
+	Tracing    TracingConfig    `yaml:"tracing" toml:"tracing"`
+	Health     HealthConfig     `yaml:"health" toml:"health"`
 }
 
-func LoadConfig() *Config {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("No .env file found, using environment variables")
-	}
-	dsn := getEnv("DB_DSN", "")
-	if dsn == "" {
-		log.Fatal("DB_DSN must be set in environment or .env file")
+type ServerConfig struct {
+	Port                      string `yaml:"port" toml:"port" env:"PORT"`
+	ReadTimeoutSeconds        int    `yaml:"read_timeout_seconds" toml:"read_timeout_seconds" env:"HTTP_READ_TIMEOUT_SECONDS"`
+	WriteTimeoutSeconds       int    `yaml:"write_timeout_seconds" toml:"write_timeout_seconds" env:"HTTP_WRITE_TIMEOUT_SECONDS"`
+	IdleTimeoutSeconds        int    `yaml:"idle_timeout_seconds" toml:"idle_timeout_seconds" env:"HTTP_IDLE_TIMEOUT_SECONDS"`
+	ShutdownTimeoutSeconds    int    `yaml:"shutdown_timeout_seconds" toml:"shutdown_timeout_seconds" env:"SHUTDOWN_TIMEOUT_SECONDS"`
+	ShutdownDrainDelaySeconds int    `yaml:"shutdown_drain_delay_seconds" toml:"shutdown_drain_delay_seconds" env:"SHUTDOWN_DRAIN_DELAY_SECONDS"`
+	// TrustedProxies is a comma-separated list of IPs and CIDRs whose
+	// X-Forwarded-For header is believed when finding the client IP. Empty
+	// trusts no proxy, so the client IP is the connection's address.
+	TrustedProxies string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+// TrustedProxyList splits TrustedProxies; it is nil when none are set.
+func (c ServerConfig) TrustedProxyList() []string {
+	var proxies []string
+	for _, p := range strings.Split(c.TrustedProxies, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
 	}
+	return proxies
+}
+
+type DatabaseConfig struct {
+	DSN                    string `yaml:"dsn" toml:"dsn" env:"DB_DSN" secret:"dsn"`
+	MaxOpenConns           int    `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns           int    `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetimeMinutes int    `yaml:"conn_max_lifetime_minutes" toml:"conn_max_lifetime_minutes" env:"DB_CONN_MAX_LIFETIME_MINUTES"`
+	ConnMaxIdleTimeMinutes int    `yaml:"conn_max_idle_time_minutes" toml:"conn_max_idle_time_minutes" env:"DB_CONN_MAX_IDLE_TIME_MINUTES"`
+	// Migrations decides what the server does with pending migrations at
+	// startup: "check" refuses to start, "up" applies them and "ignore"
+	// starts anyway.
+	Migrations string `yaml:"migrations" toml:"migrations" env:"DB_MIGRATIONS"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" toml:"addr" env:"REDIS_ADDR"`
+	Password string `yaml:"password" toml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB       int    `yaml:"db" toml:"db" env:"REDIS_DB"`
+	// PoolSize of 0 keeps go-redis' default of 10 connections per CPU.
+	PoolSize           int `yaml:"pool_size" toml:"pool_size" env:"REDIS_POOL_SIZE"`
+	DialTimeoutSeconds int `yaml:"dial_timeout_seconds" toml:"dial_timeout_seconds" env:"REDIS_DIAL_TIMEOUT_SECONDS"`
+	ReadTimeoutSeconds int `yaml:"read_timeout_seconds" toml:"read_timeout_seconds" env:"REDIS_READ_TIMEOUT_SECONDS"`
+}
+
+// CacheConfig holds the TTL of each job cache key family and the cache
+// stampede protection (see jobs.StampedeOptions).
+type CacheConfig struct {
+	JobTTLMinutes    int  `yaml:"job_ttl_minutes" toml:"job_ttl_minutes" env:"CACHE_JOB_TTL_MINUTES" reload:"true"`
+	ListTTLMinutes   int  `yaml:"list_ttl_minutes" toml:"list_ttl_minutes" env:"CACHE_LIST_TTL_MINUTES" reload:"true"`
+	SearchTTLMinutes int  `yaml:"search_ttl_minutes" toml:"search_ttl_minutes" env:"CACHE_SEARCH_TTL_MINUTES" reload:"true"`
+	Lock             bool `yaml:"lock" toml:"lock" env:"CACHE_LOCK" reload:"true"`
+	LockTTLMs        int  `yaml:"lock_ttl_ms" toml:"lock_ttl_ms" env:"CACHE_LOCK_TTL_MS" reload:"true"`
+	LockWaitMs       int  `yaml:"lock_wait_ms" toml:"lock_wait_ms" env:"CACHE_LOCK_WAIT_MS" reload:"true"`
+	EarlyRefresh     bool `yaml:"early_refresh" toml:"early_refresh" env:"CACHE_EARLY_REFRESH" reload:"true"`
+}
+
+// PaginationConfig applies to every paginated list endpoint.
+type PaginationConfig struct {
+	DefaultLimit int `yaml:"default_limit" toml:"default_limit" env:"PAGINATION_DEFAULT_LIMIT"`
+	MaxLimit     int `yaml:"max_limit" toml:"max_limit" env:"PAGINATION_MAX_LIMIT"`
+}
+
+// JobsConfig limits the text of job postings, in characters. The limits
+// cannot exceed the sizes of the database columns. DefaultLanguage is the
+// language of jobs posted without one, as a BCP 47 tag.
+type JobsConfig struct {
+	TitleMaxLength       int    `yaml:"title_max_length" toml:"title_max_length" env:"JOBS_TITLE_MAX_LENGTH"`
+	DescriptionMaxLength int    `yaml:"description_max_length" toml:"description_max_length" env:"JOBS_DESCRIPTION_MAX_LENGTH"`
+	CompanyMaxLength     int    `yaml:"company_max_length" toml:"company_max_length" env:"JOBS_COMPANY_MAX_LENGTH"`
+	CityMaxLength        int    `yaml:"city_max_length" toml:"city_max_length" env:"JOBS_CITY_MAX_LENGTH"`
+	DefaultLanguage      string `yaml:"default_language" toml:"default_language" env:"JOBS_DEFAULT_LANGUAGE"`
+}
+
+type FeaturesConfig struct {
+	Metrics   bool `yaml:"metrics" toml:"metrics" env:"METRICS_ENABLED" reload:"true"`
+	RateLimit bool `yaml:"rate_limit" toml:"rate_limit" env:"RATE_LIMIT_ENABLED" reload:"true"`
+}
+
+type AuthConfig struct {
+	Disabled       bool   `yaml:"disabled" toml:"disabled" env:"AUTH_DISABLED"`
+	ProtectReads   bool   `yaml:"protect_reads" toml:"protect_reads" env:"AUTH_PROTECT_READS"`
+	HMACSecret     string `yaml:"jwt_hs256_secret" toml:"jwt_hs256_secret" env:"JWT_HS256_SECRET" secret:"true"`
+	PublicKeyFile  string `yaml:"jwt_public_key_file" toml:"jwt_public_key_file" env:"JWT_PUBLIC_KEY_FILE"`
+	JWKSFile       string `yaml:"jwt_jwks_file" toml:"jwt_jwks_file" env:"JWT_JWKS_FILE"`
+	Issuer         string `yaml:"jwt_issuer" toml:"jwt_issuer" env:"JWT_ISSUER"`
+	Audience       string `yaml:"jwt_audience" toml:"jwt_audience" env:"JWT_AUDIENCE"`
+	RBACPolicyFile string `yaml:"rbac_policy_file" toml:"rbac_policy_file" env:"RBAC_POLICY_FILE"`
+
+	AccessTokenTTLMinutes      int `yaml:"access_token_ttl_minutes" toml:"access_token_ttl_minutes" env:"ACCESS_TOKEN_TTL_MINUTES"`
+	RefreshTokenTTLHours       int `yaml:"refresh_token_ttl_hours" toml:"refresh_token_ttl_hours" env:"REFRESH_TOKEN_TTL_HOURS"`
+	LoginMaxAttemptsPerAccount int `yaml:"login_max_attempts_per_account" toml:"login_max_attempts_per_account" env:"LOGIN_MAX_ATTEMPTS_PER_ACCOUNT"`
+	LoginMaxAttemptsPerIP      int `yaml:"login_max_attempts_per_ip" toml:"login_max_attempts_per_ip" env:"LOGIN_MAX_ATTEMPTS_PER_IP"`
+	LoginThrottleWindowMinutes int `yaml:"login_throttle_window_minutes" toml:"login_throttle_window_minutes" env:"LOGIN_THROTTLE_WINDOW_MINUTES"`
+}
+
+type RateLimitConfig struct {
+	File string `yaml:"file" toml:"file" env:"RATE_LIMIT_FILE" reload:"true"`
+}
+
+type AlertsConfig struct {
+	Notifier     string `yaml:"notifier" toml:"notifier" env:"ALERT_NOTIFIER"`
+	FilePath     string `yaml:"file_path" toml:"file_path" env:"ALERT_FILE_PATH"`
+	WebhookURL   string `yaml:"webhook_url" toml:"webhook_url" env:"ALERT_WEBHOOK_URL"`
+	SMTPAddr     string `yaml:"smtp_addr" toml:"smtp_addr" env:"SMTP_ADDR"`
+	SMTPUsername string `yaml:"smtp_username" toml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+	SMTPFrom     string `yaml:"smtp_from" toml:"smtp_from" env:"SMTP_FROM"`
+}
+
+type EventsConfig struct {
+	// Stream is the Redis stream job events are published to; empty
+	// disables publishing.
+	Stream       string `yaml:"stream" toml:"stream" env:"EVENT_STREAM"`
+	StreamMaxLen int64  `yaml:"stream_max_len" toml:"stream_max_len" env:"EVENT_STREAM_MAXLEN"`
+}
+
+type LoggingConfig struct {
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL" reload:"true"`
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT"`
+}
+
+type TracingConfig struct {
+	Exporter string `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER"`
+}
+
+type HealthConfig struct {
+	CheckTimeoutMs    int  `yaml:"check_timeout_ms" toml:"check_timeout_ms" env:"HEALTH_CHECK_TIMEOUT_MS"`
+	ReadyRequireCache bool `yaml:"ready_require_cache" toml:"ready_require_cache" env:"READY_REQUIRE_CACHE"`
+}
+
+func Default() *Config {
 	return &Config{
-		DBDSN:     dsn,
-		Port:      getEnv("PORT", "8080"),
-		RedisAddr: getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisDB:   getEnvAsInt("REDIS_DB", 0),
-		RedisPass: getEnv("REDIS_PASSWORD", ""),
+		Server: ServerConfig{
+			Port:                   "8080",
+			ReadTimeoutSeconds:     15,
+			WriteTimeoutSeconds:    30,
+			IdleTimeoutSeconds:     120,
+			ShutdownTimeoutSeconds: 30,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:           25,
+			MaxIdleConns:           10,
+			ConnMaxLifetimeMinutes: 30,
+			ConnMaxIdleTimeMinutes: 5,
+			Migrations:             "check",
+		},
+		Redis: RedisConfig{
+			Addr:               "localhost:6379",
+			DialTimeoutSeconds: 5,
+			ReadTimeoutSeconds: 3,
+		},
+		Cache: CacheConfig{
+			JobTTLMinutes:    30,
+			ListTTLMinutes:   15,
+			SearchTTLMinutes: 10,
+			LockTTLMs:        2000,
+			LockWaitMs:       1000,
+			EarlyRefresh:     true,
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: 10,
+			MaxLimit:     100,
+		},
+		Jobs: JobsConfig{
+			TitleMaxLength:       150,
+			DescriptionMaxLength: 10000,
+			CompanyMaxLength:     255,
+			CityMaxLength:        100,
+			DefaultLanguage:      "en",
+		},
+		Features: FeaturesConfig{
+			Metrics:   true,
+			RateLimit: true,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTLMinutes:      15,
+			RefreshTokenTTLHours:       720,
+			LoginMaxAttemptsPerAccount: 5,
+			LoginMaxAttemptsPerIP:      20,
+			LoginThrottleWindowMinutes: 15,
+		},
+		Alerts: AlertsConfig{
+			Notifier: "log",
+			FilePath: "alerts.log",
+		},
+		Events: EventsConfig{
+			Stream:       "jobs:events",
+			StreamMaxLen: 100000,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+		},
+		Health: HealthConfig{
+			CheckTimeoutMs: 1000,
+		},
 	}
 }
 
-func getEnv(key, fallback string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// dotenv loads .env once; it never overrides variables that are already
+// set, so reading it again on reload would change nothing.
+var dotenv sync.Once
+
+// Load builds the effective configuration from the defaults, the config
+// file at path (YAML or TOML by extension; optional) and the environment,
+// including a .env file if present. Callers run Validate before using it.
+func Load(path string) (*Config, error) {
+	dotenv.Do(func() {
+		if err := godotenv.Load(); err != nil {
+			slog.Info("No .env file found, using environment variables")
+		}
+	})
+	cfg := Default()
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
 	}
-	return fallback
+	return cfg, nil
 }
 
-func getEnvAsInt(key string, fallback int) int {
-	if value, exists := os.LookupEnv(key); exists {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
+// loadFile decodes path over cfg. Unknown keys are rejected so that a typo
+// does not silently leave a default in place.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("config: %s: %w", path, err)
+		}
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			var strict *toml.StrictMissingError
+			if errors.As(err, &strict) {
+				keys := make([]string, len(strict.Errors))
+				for i, e := range strict.Errors {
+					row, _ := e.Position()
+					keys[i] = fmt.Sprintf("%s (line %d)", strings.Join(e.Key(), "."), row)
+				}
+				return fmt.Errorf("config: %s: unknown keys: %s", path, strings.Join(keys, ", "))
+			}
+			return fmt.Errorf("config: %s: %w", path, err)
 		}
+	default:
+		return fmt.Errorf("config: %s: unsupported file type, want .yaml, .yml or .toml", path)
 	}
-	return fallback
+	return nil
 }
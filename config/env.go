@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+// applyEnv overrides every field that has an env tag and whose variable is
+// set. Unlike a missing variable, an unparsable one is an error.
+func applyEnv(cfg *Config) error {
+	var errs []error
+	walk(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, sf reflect.StructField, _ string) {
+		name := sf.Tag.Get("env")
+		if name == "" {
+			return
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if err := setField(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// walk calls fn for every leaf field of the section structs, with its dotted
+// YAML key (e.g. "server.port").
+func walk(v reflect.Value, prefix string, fn func(field reflect.Value, sf reflect.StructField, key string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := sf.Tag.Get("yaml")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if sf.Type.Kind() == reflect.Struct {
+			walk(v.Field(i), key, fn)
+			continue
+		}
+		fn(v.Field(i), sf, key)
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"io"
+	"reflect"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const mask = "********"
+
+// dsnPassword matches the password of a go-sql-driver DSN
+// (user:password@tcp(host)/db).
+var dsnPassword = regexp.MustCompile(`^([^:@/]*):(.*)@`)
+
+// Print writes the configuration as YAML with secrets masked.
+func (c *Config) Print(w io.Writer) error {
+	masked := *c
+	walk(reflect.ValueOf(&masked).Elem(), "", func(field reflect.Value, sf reflect.StructField, _ string) {
+		if field.Kind() == reflect.String {
+			field.SetString(maskSecret(sf, field.String()))
+		}
+	})
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&masked); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// maskSecret hides value if sf is tagged secret. Empty secrets are left
+// empty so that a missing one is still visible.
+func maskSecret(sf reflect.StructField, value string) string {
+	if value == "" {
+		return value
+	}
+	switch sf.Tag.Get("secret") {
+	case "true":
+		return mask
+	case "dsn":
+		return dsnPassword.ReplaceAllString(value, "${1}:"+mask+"@")
+	}
+	return value
+}
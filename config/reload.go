@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Change is a setting that differs between two configurations. Secrets are
+// masked in Old and New.
+type Change struct {
+	Key        string
+	Old        string
+	New        string
+	Reloadable bool
+}
+
+func (ch Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", ch.Key, ch.Old, ch.New)
+}
+
+// Reload reads the configuration again from path and the environment. It
+// returns the configuration to run with, which is c with only the
+// reloadable settings taken from the new one, and every change found;
+// changes that are not Reloadable need a restart and are not applied. An
+// invalid new configuration is an error and nothing is applied.
+func (c *Config) Reload(path string) (*Config, []Change, error) {
+	loaded, err := Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := loaded.Validate(); err != nil {
+		return nil, nil, err
+	}
+	next := *c
+	var changes []Change
+	nextFields := fields(&next)
+	loadedFields := fields(loaded)
+	for i, old := range fields(c) {
+		updated := loadedFields[i]
+		if reflect.DeepEqual(old.value.Interface(), updated.value.Interface()) {
+			continue
+		}
+		ch := Change{
+			Key:        old.key,
+			Old:        display(old),
+			New:        display(updated),
+			Reloadable: old.sf.Tag.Get("reload") == "true",
+		}
+		if ch.Reloadable {
+			nextFields[i].value.Set(updated.value)
+		}
+		changes = append(changes, ch)
+	}
+	return &next, changes, nil
+}
+
+type field struct {
+	key   string
+	sf    reflect.StructField
+	value reflect.Value
+}
+
+// fields lists the leaf settings of cfg in declaration order.
+func fields(cfg *Config) []field {
+	var out []field
+	walk(reflect.ValueOf(cfg).Elem(), "", func(v reflect.Value, sf reflect.StructField, key string) {
+		out = append(out, field{key: key, sf: sf, value: v})
+	})
+	return out
+}
+
+func display(f field) string {
+	if f.value.Kind() == reflect.String {
+		return fmt.Sprintf("%q", maskSecret(f.sf, f.value.String()))
+	}
+	return fmt.Sprint(f.value.Interface())
+}
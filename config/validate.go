@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Validate reports every invalid setting at once, each prefixed with its
+// config file key.
+func (c *Config) Validate() error {
+	var v validator
+
+	v.check(c.Database.DSN != "", "database.dsn", "is required (set DB_DSN or database.dsn)")
+	port, err := strconv.Atoi(c.Server.Port)
+	v.check(err == nil && port > 0 && port < 65536, "server.port", "must be a port number between 1 and 65535, got %q", c.Server.Port)
+	v.positive("server.read_timeout_seconds", c.Server.ReadTimeoutSeconds)
+	v.positive("server.write_timeout_seconds", c.Server.WriteTimeoutSeconds)
+	v.positive("server.idle_timeout_seconds", c.Server.IdleTimeoutSeconds)
+	v.positive("server.shutdown_timeout_seconds", c.Server.ShutdownTimeoutSeconds)
+	v.nonNegative("server.shutdown_drain_delay_seconds", c.Server.ShutdownDrainDelaySeconds)
+	for _, p := range c.Server.TrustedProxyList() {
+		_, prefixErr := netip.ParsePrefix(p)
+		_, addrErr := netip.ParseAddr(p)
+		v.check(prefixErr == nil || addrErr == nil, "server.trusted_proxies", "must list IP addresses or CIDR ranges, got %q", p)
+	}
+
+	v.nonNegative("database.max_open_conns", c.Database.MaxOpenConns)
+	v.nonNegative("database.max_idle_conns", c.Database.MaxIdleConns)
+	v.check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns", "must not exceed database.max_open_conns (%d), got %d", c.Database.MaxOpenConns, c.Database.MaxIdleConns)
+	v.nonNegative("database.conn_max_lifetime_minutes", c.Database.ConnMaxLifetimeMinutes)
+	v.nonNegative("database.conn_max_idle_time_minutes", c.Database.ConnMaxIdleTimeMinutes)
+	v.oneOf("database.migrations", strings.ToLower(c.Database.Migrations), "check", "up", "ignore")
+
+	v.check(c.Redis.Addr != "", "redis.addr", "is required")
+	v.nonNegative("redis.db", c.Redis.DB)
+	v.nonNegative("redis.pool_size", c.Redis.PoolSize)
+	v.positive("redis.dial_timeout_seconds", c.Redis.DialTimeoutSeconds)
+	v.positive("redis.read_timeout_seconds", c.Redis.ReadTimeoutSeconds)
+
+	v.positive("cache.job_ttl_minutes", c.Cache.JobTTLMinutes)
+	v.positive("cache.list_ttl_minutes", c.Cache.ListTTLMinutes)
+	v.positive("cache.search_ttl_minutes", c.Cache.SearchTTLMinutes)
+	v.positive("cache.lock_ttl_ms", c.Cache.LockTTLMs)
+	v.positive("cache.lock_wait_ms", c.Cache.LockWaitMs)
+	v.check(c.Cache.LockWaitMs <= c.Cache.LockTTLMs, "cache.lock_wait_ms",
+		"must not exceed cache.lock_ttl_ms (%d), got %d", c.Cache.LockTTLMs, c.Cache.LockWaitMs)
+
+	v.positive("pagination.default_limit", c.Pagination.DefaultLimit)
+	v.positive("pagination.max_limit", c.Pagination.MaxLimit)
+	v.check(c.Pagination.DefaultLimit <= c.Pagination.MaxLimit, "pagination.default_limit",
+		"must not exceed pagination.max_limit (%d), got %d", c.Pagination.MaxLimit, c.Pagination.DefaultLimit)
+
+	// VARCHAR(255), TEXT (65535 bytes, up to 4 per character) and VARCHAR(100).
+	v.between("jobs.title_max_length", c.Jobs.TitleMaxLength, 1, 255)
+	v.between("jobs.description_max_length", c.Jobs.DescriptionMaxLength, 1, 16383)
+	v.between("jobs.company_max_length", c.Jobs.CompanyMaxLength, 1, 255)
+	v.between("jobs.city_max_length", c.Jobs.CityMaxLength, 1, 100)
+	lang, err := language.Parse(c.Jobs.DefaultLanguage)
+	v.check(err == nil && lang != language.Und, "jobs.default_language", "must be a BCP 47 language tag, got %q", c.Jobs.DefaultLanguage)
+
+	v.positive("auth.access_token_ttl_minutes", c.Auth.AccessTokenTTLMinutes)
+	v.positive("auth.refresh_token_ttl_hours", c.Auth.RefreshTokenTTLHours)
+	v.positive("auth.login_max_attempts_per_account", c.Auth.LoginMaxAttemptsPerAccount)
+	v.positive("auth.login_max_attempts_per_ip", c.Auth.LoginMaxAttemptsPerIP)
+	v.positive("auth.login_throttle_window_minutes", c.Auth.LoginThrottleWindowMinutes)
+
+	if c.Alerts.Notifier != "" {
+		v.oneOf("alerts.notifier", c.Alerts.Notifier, "log", "file", "webhook", "smtp")
+	}
+	v.check(c.Alerts.Notifier != "webhook" || c.Alerts.WebhookURL != "", "alerts.webhook_url", "is required when alerts.notifier is webhook")
+	v.check(c.Alerts.Notifier != "smtp" || (c.Alerts.SMTPAddr != "" && c.Alerts.SMTPFrom != ""),
+		"alerts.smtp_addr", "and alerts.smtp_from are required when alerts.notifier is smtp")
+	v.check(c.Events.StreamMaxLen >= 0, "events.stream_max_len", "must not be negative, got %d", c.Events.StreamMaxLen)
+
+	v.oneOf("logging.level", strings.ToLower(c.Logging.Level), "debug", "info", "warn", "error")
+	v.oneOf("logging.format", strings.ToLower(c.Logging.Format), "json", "text")
+	v.oneOf("tracing.exporter", strings.ToLower(c.Tracing.Exporter), "none", "stdout", "otlp")
+	v.positive("health.check_timeout_ms", c.Health.CheckTimeoutMs)
+
+	return v.err()
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, key, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+	}
+}
+
+func (v *validator) positive(key string, n int) {
+	v.check(n > 0, key, "must be greater than 0, got %d", n)
+}
+
+func (v *validator) nonNegative(key string, n int) {
+	v.check(n >= 0, key, "must not be negative, got %d", n)
+}
+
+func (v *validator) between(key string, n, lo, hi int) {
+	v.check(n >= lo && n <= hi, key, "must be between %d and %d, got %d", lo, hi, n)
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(v.errs))
+	for i, err := range v.errs {
+		msgs[i] = "  - " + err.Error()
+	}
+	return errors.New("invalid configuration:\n" + strings.Join(msgs, "\n"))
+}
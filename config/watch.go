@@ -0,0 +1,100 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounce groups the burst of events an editor produces when saving.
+const debounce = 500 * time.Millisecond
+
+// Watch calls reload on SIGHUP and whenever one of files is written,
+// created or replaced, until ctx is done. Empty paths are ignored.
+// Directories are watched rather than the files themselves, because
+// editors and Kubernetes ConfigMap updates replace files instead of
+// writing them in place. The signal and the watches are registered before
+// Watch returns; the events are handled in a goroutine.
+func Watch(ctx context.Context, files []string, reload func()) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		signal.Stop(hup)
+		return err
+	}
+	watched, err := addWatches(watcher, files)
+	if err != nil {
+		signal.Stop(hup)
+		watcher.Close()
+		return err
+	}
+	go func() {
+		defer signal.Stop(hup)
+		defer watcher.Close()
+		watch(ctx, watcher, watched, hup, reload)
+	}()
+	return nil
+}
+
+// addWatches watches the directories of files and returns the absolute
+// paths of the files.
+func addWatches(watcher *fsnotify.Watcher, files []string) (map[string]bool, error) {
+	watched := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		watched[abs] = true
+		dir := filepath.Dir(abs)
+		if !dirs[dir] {
+			if err := watcher.Add(dir); err != nil {
+				return nil, err
+			}
+			dirs[dir] = true
+		}
+	}
+	return watched, nil
+}
+
+func watch(ctx context.Context, watcher *fsnotify.Watcher, watched map[string]bool, hup <-chan os.Signal, reload func()) {
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			slog.Info("config: SIGHUP received, reloading")
+			reload()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			// ConfigMap volumes swap the ..data symlink on update.
+			if watched[event.Name] || filepath.Base(event.Name) == "..data" {
+				pending = time.After(debounce)
+			}
+		case <-pending:
+			pending = nil
+			slog.Info("config: file changed, reloading")
+			reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("config: file watch failed", "error", err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package apperr defines the errors repositories and services return for
+// conditions a client can act on. Handlers hand them to problem.Error, which
+// maps each kind to an HTTP status.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+const errDuplicateEntry = 1062
+
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+)
+
+// Error is an error of one of the kinds above. Its message is shown to
+// clients; the cause, if any, is only logged.
+type Error struct {
+	Kind    error
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError describes one invalid field of a request body. Field is the
+// JSON path, such as "stages[0].name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+func NotFound(format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...any) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func Validation(format string, args ...any) error {
+	return &Error{Kind: ErrValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// InvalidFields reports a request body whose fields failed validation.
+func InvalidFields(fields ...FieldError) error {
+	return &Error{Kind: ErrValidation, Message: "The request body is invalid", Fields: fields}
+}
+
+// FromDB translates a missing row into ErrNotFound and a duplicate key into
+// ErrConflict, naming resource in the message. Other errors are returned
+// unchanged.
+func FromDB(err error, resource string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Kind: ErrNotFound, Message: resource + " not found", Err: err}
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return &Error{Kind: ErrConflict, Message: resource + " already exists", Err: err}
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the JWT claims the service understands. Roles and Company are
+// optional private claims used by later authorization checks. SessionID is
+// set on tokens issued by this service's own login endpoint.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles     []string `json:"roles,omitempty"`
+	Company   string   `json:"company,omitempty"`
+	SessionID string   `json:"sid,omitempty"`
+}
+
+type KeyConfig struct {
+	// HMACSecret enables HS256 tokens.
+	HMACSecret string
+	// PublicKeyFile is a PEM file with one or more RSA or EC (P-256) public
+	// keys for RS256 and ES256 tokens.
+	PublicKeyFile string
+	// JWKSFile is a local JSON Web Key Set; its keys are selected by "kid".
+	JWKSFile string
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Verifier validates HS256, RS256 and ES256 tokens. Tokens with a "kid"
+// header are checked against the key with that ID only; tokens without one
+// are checked against every key of the matching type.
+type Verifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	ecKeys     map[string]*ecdsa.PublicKey
+	parser     *jwt.Parser
+}
+
+var ErrNoKeys = errors.New("no JWT verification keys configured")
+
+func NewVerifier(cfg KeyConfig) (*Verifier, error) {
+	v := &Verifier{
+		rsaKeys: make(map[string]*rsa.PublicKey),
+		ecKeys:  make(map[string]*ecdsa.PublicKey),
+	}
+	if cfg.HMACSecret != "" {
+		v.hmacSecret = []byte(cfg.HMACSecret)
+	}
+	if cfg.PublicKeyFile != "" {
+		if err := v.loadPEM(cfg.PublicKeyFile); err != nil {
+			return nil, fmt.Errorf("load %s: %w", cfg.PublicKeyFile, err)
+		}
+	}
+	if cfg.JWKSFile != "" {
+		if err := v.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, fmt.Errorf("load %s: %w", cfg.JWKSFile, err)
+		}
+	}
+	if v.hmacSecret == nil && len(v.rsaKeys) == 0 && len(v.ecKeys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256", "ES256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+func (v *Verifier) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(tokenString, claims, v.keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	switch token.Method.Alg() {
+	case "HS256":
+		if v.hmacSecret == nil {
+			return nil, errors.New("HS256 tokens are not accepted")
+		}
+		return v.hmacSecret, nil
+	case "RS256":
+		return selectKey(v.rsaKeys, kid)
+	case "ES256":
+		return selectKey(v.ecKeys, kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+func selectKey[K any](keys map[string]K, kid string) (interface{}, error) {
+	if kid != "" {
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	var set jwt.VerificationKeySet
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key)
+	}
+	if len(set.Keys) == 0 {
+		return nil, errors.New("no key for signing method")
+	}
+	return set, nil
+}
+
+func (v *Verifier) loadPEM(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			if i == 0 {
+				return errors.New("no PEM blocks found")
+			}
+			return nil
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		kid := fmt.Sprintf("pem-%d", i)
+		if err := v.addKey(kid, pub); err != nil {
+			return err
+		}
+	}
+}
+
+func (v *Verifier) addKey(kid string, key interface{}) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		v.rsaKeys[kid] = k
+	case *ecdsa.PublicKey:
+		if k.Curve.Params().Name != "P-256" {
+			return fmt.Errorf("key %s: ES256 requires a P-256 key", kid)
+		}
+		v.ecKeys[kid] = k
+	default:
+		return fmt.Errorf("key %s: unsupported key type %T", kid, key)
+	}
+	return nil
+}
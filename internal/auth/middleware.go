@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+type contextKey struct{}
+
+const (
+	ginClaimsKey    = "auth.claims"
+	ginPrincipalKey = "auth.principal"
+
+	HeaderAPIKey = "X-API-Key"
+)
+
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyValidator resolves an API key to the principal it belongs to.
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// SessionValidator reports whether a login session is still active, so that
+// tokens carrying a "sid" claim stop working once the session is revoked.
+type SessionValidator interface {
+	SessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// Authenticate resolves the caller from a bearer JWT or an X-API-Key header
+// and stores the principal (and JWT claims) in the Gin and request contexts.
+// Requests without credentials pass through; Require decides whether they
+// are allowed. Any argument may be nil to disable that check.
+func Authenticate(v *Verifier, keys APIKeyValidator, sessions SessionValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if header := c.GetHeader("Authorization"); header != "" {
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || token == "" {
+				unauthorized(c, "Malformed Authorization header")
+				return
+			}
+			if v == nil {
+				unauthorized(c, "Bearer tokens are not accepted")
+				return
+			}
+			claims, err := v.Verify(token)
+			if err != nil {
+				unauthorized(c, "Invalid token")
+				return
+			}
+			if claims.SessionID != "" && sessions != nil {
+				active, err := sessions.SessionActive(c.Request.Context(), claims.SessionID)
+				if err != nil {
+					problem.Error(c, err)
+					return
+				}
+				if !active {
+					unauthorized(c, "Session has been revoked")
+					return
+				}
+			}
+			c.Set(ginClaimsKey, claims)
+			ctx := context.WithValue(c.Request.Context(), contextKey{}, claims)
+			setPrincipal(c, ctx, UserPrincipal(claims))
+		} else if key := c.GetHeader(HeaderAPIKey); key != "" {
+			if keys == nil {
+				unauthorized(c, "API keys are not accepted")
+				return
+			}
+			principal, err := keys.ValidateAPIKey(c.Request.Context(), key)
+			if errors.Is(err, ErrInvalidAPIKey) {
+				unauthorized(c, "Invalid API key")
+				return
+			}
+			if err != nil {
+				problem.Error(c, err)
+				return
+			}
+			setPrincipal(c, c.Request.Context(), principal)
+		}
+		c.Next()
+	}
+}
+
+// Require rejects requests that did not present valid credentials.
+func Require() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetPrincipal(c) == nil {
+			unauthorized(c, "Authentication required")
+			return
+		}
+		c.Next()
+	}
+}
+
+func GetClaims(c *gin.Context) *Claims {
+	if v, ok := c.Get(ginClaimsKey); ok {
+		return v.(*Claims)
+	}
+	return nil
+}
+
+func ClaimsFromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(contextKey{}).(*Claims)
+	return claims
+}
+
+func GetPrincipal(c *gin.Context) *Principal {
+	if v, ok := c.Get(ginPrincipalKey); ok {
+		return v.(*Principal)
+	}
+	return nil
+}
+
+func setPrincipal(c *gin.Context, ctx context.Context, p *Principal) {
+	c.Set(ginPrincipalKey, p)
+	c.Request = c.Request.WithContext(WithPrincipal(ctx, p))
+}
+
+func unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	problem.Abort(c, problem.TypeUnauthorized, message)
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+const ginPolicyKey = "auth.policy"
+
+//go:embed default_policy.yaml
+var defaultPolicy []byte
+
+// Policy is the role/action permission matrix. Actions are plain strings
+// such as "jobs:create"; a grant of "*" allows everything and a grant ending
+// in ":*" allows every action with that prefix.
+type Policy struct {
+	Roles map[string][]string `yaml:"roles"`
+	// DefaultRoles apply to users whose token carries no roles.
+	DefaultRoles []string `yaml:"default_roles"`
+	// APIKeyRoles apply to callers authenticated with a company API key.
+	APIKeyRoles []string `yaml:"api_key_roles"`
+	// AnonymousRoles apply to requests without credentials on public routes.
+	AnonymousRoles []string `yaml:"anonymous_roles"`
+}
+
+// LoadPolicy reads a policy from a YAML file, or returns the built-in
+// policy when path is empty.
+func LoadPolicy(path string) (*Policy, error) {
+	data := defaultPolicy
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read policy: %w", err)
+		}
+	}
+	return ParsePolicy(data)
+}
+
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	if len(p.Roles) == 0 {
+		return nil, fmt.Errorf("policy defines no roles")
+	}
+	for _, set := range [][]string{p.DefaultRoles, p.APIKeyRoles, p.AnonymousRoles} {
+		for _, role := range set {
+			if _, ok := p.Roles[role]; !ok {
+				return nil, fmt.Errorf("policy references undefined role %q", role)
+			}
+		}
+	}
+	return &p, nil
+}
+
+// Allows reports whether the principal may perform action. A nil principal
+// is evaluated with the anonymous roles.
+func (p *Policy) Allows(principal *Principal, action string) bool {
+	for _, role := range p.rolesFor(principal) {
+		for _, grant := range p.Roles[role] {
+			if grantMatches(grant, action) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Policy) rolesFor(principal *Principal) []string {
+	switch {
+	case principal == nil:
+		return p.AnonymousRoles
+	case principal.Kind == KindAPIKey:
+		return slices.Concat(p.APIKeyRoles, principal.Roles)
+	case len(principal.Roles) == 0:
+		return p.DefaultRoles
+	}
+	return principal.Roles
+}
+
+func grantMatches(grant, action string) bool {
+	if grant == "*" || grant == action {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(grant, "*")
+	return ok && strings.HasSuffix(prefix, ":") && strings.HasPrefix(action, prefix)
+}
+
+// Authorize rejects requests whose principal may not perform action and
+// makes the policy available to Can for finer-grained checks in handlers.
+func Authorize(p *Policy, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ginPolicyKey, p)
+		if !p.Allows(GetPrincipal(c), action) {
+			Forbid(c, action, "Insufficient permissions")
+			return
+		}
+		c.Next()
+	}
+}
+
+// Can reports whether the caller may perform action. Without a policy on
+// the route (authentication disabled) everything is allowed.
+func Can(c *gin.Context, action string) bool {
+	v, ok := c.Get(ginPolicyKey)
+	if !ok {
+		return true
+	}
+	return v.(*Policy).Allows(GetPrincipal(c), action)
+}
+
+// Forbid logs the denial and aborts the request with 403.
+func Forbid(c *gin.Context, action, message string) {
+	principal := "anonymous"
+	var roles []string
+	if p := GetPrincipal(c); p != nil {
+		principal, roles = p.ID, p.Roles
+	}
+	slog.WarnContext(c.Request.Context(), "auth: permission denied", "action", action,
+		"principal", principal, "roles", roles, "method", c.Request.Method, "route", c.FullPath())
+	problem.Abort(c, problem.TypeForbidden, message)
+}
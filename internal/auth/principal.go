@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	KindUser   = "user"
+	KindAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller, whether it presented a JWT or an
+// API key. API keys act on behalf of their company, so every key of a
+// company shares the same principal ID.
+type Principal struct {
+	ID      string
+	Kind    string
+	Subject string
+	Company string
+	Roles   []string
+	// KeyID identifies the API key used, for API key principals.
+	KeyID string
+}
+
+func UserPrincipal(claims *Claims) *Principal {
+	return &Principal{
+		ID:      "user:" + claims.Subject,
+		Kind:    KindUser,
+		Subject: claims.Subject,
+		Company: claims.Company,
+		Roles:   claims.Roles,
+	}
+}
+
+func CompanyPrincipal(company, keyName, keyID string) *Principal {
+	return &Principal{
+		ID:      CompanyOwnerID(company),
+		Kind:    KindAPIKey,
+		Subject: keyName,
+		Company: company,
+		KeyID:   keyID,
+	}
+}
+
+// CompanyOwnerID is the owner recorded on resources created with a
+// company's API keys.
+func CompanyOwnerID(company string) string {
+	return "company:" + company
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// Owns reports whether the principal may modify a resource recorded with
+// ownerID: its own resources, or its company's when it belongs to one.
+func (p *Principal) Owns(ownerID string) bool {
+	if ownerID == "" {
+		return false
+	}
+	if ownerID == p.ID {
+		return true
+	}
+	return p.Company != "" && ownerID == CompanyOwnerID(p.Company)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
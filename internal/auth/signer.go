@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrNoSigningKey = errors.New("JWT_HS256_SECRET is required to issue tokens")
+
+// Signer issues HS256 access tokens for accounts managed by this service.
+// Tokens use the same issuer and audience the Verifier checks.
+type Signer struct {
+	secret   []byte
+	issuer   string
+	audience string
+	ttl      time.Duration
+}
+
+func NewSigner(cfg KeyConfig, ttl time.Duration) (*Signer, error) {
+	if cfg.HMACSecret == "" {
+		return nil, ErrNoSigningKey
+	}
+	return &Signer{
+		secret:   []byte(cfg.HMACSecret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		ttl:      ttl,
+	}, nil
+}
+
+func (s *Signer) TTL() time.Duration {
+	return s.ttl
+}
+
+// Sign fills in the registered time, issuer and audience claims and returns
+// the signed token.
+func (s *Signer) Sign(claims Claims) (string, error) {
+	now := time.Now()
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(s.ttl))
+	if s.issuer != "" {
+		claims.Issuer = s.issuer
+	}
+	if s.audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.audience}
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// Check is a dependency probed by the readiness endpoint. A failing
+// critical check makes the service not ready; a failing non-critical one
+// only marks it degraded.
+type Check struct {
+	Name     string
+	Critical bool
+	Ping     func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type Handler struct {
+	checks   []Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+func NewHandler(timeout time.Duration, checks ...Check) *Handler {
+	return &Handler{checks: checks, timeout: timeout}
+}
+
+// Live reports that the process is up and serving HTTP. It never touches
+// dependencies, so an outage of MySQL or Redis does not get the service
+// restarted.
+func (h *Handler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// Drain makes the readiness endpoint fail from now on, so load balancers
+// stop routing new requests while in-flight ones finish.
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// Ready pings every dependency concurrently, each bounded by the timeout,
+// and answers 503 when a critical one is down or the service is draining.
+func (h *Handler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, Report{Status: StatusDraining, Checks: map[string]CheckResult{}})
+		return
+	}
+	report := h.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status == StatusUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+func (h *Handler) Check(ctx context.Context) Report {
+	results := make([]CheckResult, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(h.checks))}
+	for i, check := range h.checks {
+		res := results[i]
+		report.Checks[check.Name] = res
+		if res.Status == StatusUp {
+			continue
+		}
+		if check.Critical {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (h *Handler) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	start := time.Now()
+	err := check.Ping(ctx)
+	res := CheckResult{
+		Status:    StatusUp,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger sends GORM's logs to slog: failed queries as errors, slow ones
+// as warnings and, at debug level, every statement. Queries run with a
+// request context carry its request ID.
+type GormLogger struct {
+	SlowThreshold time.Duration
+}
+
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{SlowThreshold: slowThreshold}
+}
+
+func (l *GormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	slog.InfoContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	slog.WarnContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	slog.ErrorContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		slog.ErrorContext(ctx, "query failed", "component", "gorm", "error", err,
+			"sql", sql, "rows", rows, "duration_ms", durationMs(elapsed))
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		sql, rows := fc()
+		slog.WarnContext(ctx, "slow query", "component", "gorm",
+			"sql", sql, "rows", rows, "duration_ms", durationMs(elapsed))
+	case slog.Default().Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		slog.DebugContext(ctx, "query", "component", "gorm",
+			"sql", sql, "rows", rows, "duration_ms", durationMs(elapsed))
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var enabled atomic.Bool
+
+// SetEnabled switches request metrics and the /metrics endpoint on or off
+// at runtime.
+func SetEnabled(on bool) {
+	enabled.Store(on)
+}
+
+// Middleware records request latency by route template, so /jobs/1 and
+// /jobs/2 share a series. Requests that match no route are grouped.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled.Load() {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the registry, or 404 while metrics are switched off.
+func Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+	return func(c *gin.Context) {
+		if !enabled.Load() {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "jobposting"
+
+// Registry holds every metric the service exposes on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "GORM statement latency by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "status"})
+
+	RedisCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Redis command latency by command.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command", "status"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups by key family and result (hit, miss or error).",
+	}, []string{"family", "result"})
+
+	CacheInvalidations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_invalidations_total",
+		Help:      "Cache invalidations by key family.",
+	}, []string{"family"})
+
+	CacheFills = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_fills_total",
+		Help:      "Cache entries filled by key family and source (database, or replica when another replica holding the lock filled it).",
+	}, []string{"family", "source"})
+
+	CacheEarlyRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_early_refreshes_total",
+		Help:      "Cache hits that refreshed the entry before it expired, by key family.",
+	}, []string{"family"})
+
+	OutboxMessagesRelayed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_messages_relayed_total",
+		Help:      "Outbox messages published and marked processed.",
+	})
+
+	OutboxRelayErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_relay_errors_total",
+		Help:      "Failed outbox relay rounds.",
+	})
+
+	OutboxMessagesFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_messages_failed_total",
+		Help:      "Outbox messages given up on after repeated publishing failures.",
+	})
+
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_delivery_attempts_total",
+		Help:      "Webhook delivery attempts by outcome (succeeded, retry or dead).",
+	}, []string{"outcome"})
+
+	AlertMatches = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alert_matches_total",
+		Help:      "Jobs matched against saved searches.",
+	})
+
+	AlertNotifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alert_notifications_total",
+		Help:      "Alert digests sent by frequency and outcome (sent or failed).",
+	}, []string{"frequency", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		DBQueryDuration,
+		RedisCommandDuration,
+		CacheRequests,
+		CacheInvalidations,
+		CacheFills,
+		CacheEarlyRefreshes,
+		OutboxMessagesRelayed,
+		OutboxRelayErrors,
+		OutboxMessagesFailed,
+		WebhookDeliveries,
+		AlertMatches,
+		AlertNotifications,
+	)
+}
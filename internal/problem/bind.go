@@ -0,0 +1,125 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Validation errors name fields by their JSON keys rather than the Go
+// struct fields. The singleline rule rejects CR and LF, for values that end
+// up in headers such as an email subject.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("singleline", func(fl validator.FieldLevel) bool {
+			return !strings.ContainsAny(fl.Field().String(), "\r\n")
+		})
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+	}
+}
+
+// Bind responds to an error from ShouldBindJSON: a validation problem with
+// one entry per invalid field, or a bad request if the body is not JSON.
+func Bind(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &invalid):
+		fields := make([]apperr.FieldError, len(invalid))
+		for i, fe := range invalid {
+			fields[i] = apperr.FieldError{Field: fieldPath(fe), Rule: fe.Tag(), Message: ruleMessage(fe)}
+		}
+		Error(c, apperr.InvalidFields(fields...))
+	case errors.As(err, &typeErr):
+		Error(c, apperr.InvalidFields(apperr.FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + jsonType(typeErr.Type),
+		}))
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		Abort(c, TypeBadRequest, "The request body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		Abort(c, TypeBadRequest, "The request body is empty")
+	default:
+		Abort(c, TypeBadRequest, err.Error())
+	}
+}
+
+// fieldPath drops the struct name from the namespace, leaving a path such
+// as "stages[0].name".
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	kind := fe.Kind()
+	if kind == reflect.Pointer {
+		kind = fe.Type().Elem().Kind()
+	}
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "singleline":
+		return "must not contain line breaks"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "min", "max", "len":
+		bound := map[string]string{"min": "at least ", "max": "at most ", "len": "exactly "}[fe.Tag()]
+		switch kind {
+		case reflect.String:
+			return "must be " + bound + fe.Param() + " characters long"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return "must contain " + bound + fe.Param() + " items"
+		}
+		return "must be " + bound + fe.Param()
+	}
+	if fe.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), fe.Param())
+	}
+	return "must satisfy " + fe.Tag()
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	}
+	return "an object"
+}
@@ -0,0 +1,107 @@
+// Package problem writes error responses as RFC 7807 problem details
+// (application/problem+json).
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem types. The URIs are stable, so clients can switch on them
+// instead of parsing the detail.
+const (
+	TypeBadRequest   = "/problems/bad-request"
+	TypeValidation   = "/problems/validation-error"
+	TypeUnauthorized = "/problems/unauthorized"
+	TypeForbidden    = "/problems/forbidden"
+	TypeNotFound     = "/problems/not-found"
+	TypeConflict     = "/problems/conflict"
+	TypeRateLimited  = "/problems/rate-limited"
+	TypeInternal     = "/problems/internal-error"
+)
+
+var types = map[string]struct {
+	status int
+	title  string
+}{
+	TypeBadRequest:   {http.StatusBadRequest, "Bad request"},
+	TypeValidation:   {http.StatusBadRequest, "Validation failed"},
+	TypeUnauthorized: {http.StatusUnauthorized, "Unauthorized"},
+	TypeForbidden:    {http.StatusForbidden, "Forbidden"},
+	TypeNotFound:     {http.StatusNotFound, "Not found"},
+	TypeConflict:     {http.StatusConflict, "Conflict"},
+	TypeRateLimited:  {http.StatusTooManyRequests, "Too many requests"},
+	TypeInternal:     {http.StatusInternalServerError, "Internal server error"},
+}
+
+// Problem is the response body. Errors lists the invalid fields of a
+// validation problem.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   []apperr.FieldError `json:"errors,omitempty"`
+}
+
+// Abort responds with a problem of the given type and stops the handler
+// chain.
+func Abort(c *gin.Context, typ, detail string) {
+	write(c, typ, detail, nil)
+}
+
+// Error maps err to a problem: apperr kinds become 404, 409 or 400 with
+// their message as the detail. Causes and any context wrapped around the
+// *apperr.Error are only recorded in the access log, as is the cause of a
+// 500 for anything else.
+func Error(c *gin.Context, err error) {
+	var e *apperr.Error
+	errors.As(err, &e)
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		write(c, TypeNotFound, detail(c, err, e), nil)
+	case errors.Is(err, apperr.ErrConflict):
+		write(c, TypeConflict, detail(c, err, e), nil)
+	case errors.Is(err, apperr.ErrValidation):
+		var fields []apperr.FieldError
+		if e != nil {
+			fields = e.Fields
+		}
+		write(c, TypeValidation, detail(c, err, e), fields)
+	default:
+		_ = c.Error(err)
+		write(c, TypeInternal, "", nil)
+	}
+}
+
+// detail is the message of the *apperr.Error. The rest of err is logged
+// rather than shown, since it may come from the database or a driver.
+func detail(c *gin.Context, err error, e *apperr.Error) string {
+	if e == nil {
+		_ = c.Error(err)
+		return ""
+	}
+	if err != error(e) || e.Err != nil {
+		_ = c.Error(err)
+	}
+	return e.Message
+}
+
+func write(c *gin.Context, typ, detail string, fields []apperr.FieldError) {
+	t := types[typ]
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(t.status, Problem{
+		Type:     typ,
+		Title:    t.title,
+		Status:   t.status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Errors:   fields,
+	})
+}
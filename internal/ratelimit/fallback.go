@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	fallbackLogInterval = time.Minute
+	// primaryRetryDelay is how long the primary limiter is skipped after it
+	// fails, so requests do not each wait for a dead Redis to time out.
+	primaryRetryDelay = 5 * time.Second
+)
+
+// FallbackLimiter uses the primary limiter and switches to the secondary one
+// while the primary fails, so a Redis outage neither blocks traffic nor
+// disables limiting.
+type FallbackLimiter struct {
+	primary   Limiter
+	secondary Limiter
+	retryAt   atomic.Int64
+	lastLog   atomic.Int64
+}
+
+func NewFallbackLimiter(primary, secondary Limiter) *FallbackLimiter {
+	return &FallbackLimiter{primary: primary, secondary: secondary}
+}
+
+func (l *FallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now().UnixNano()
+	if now >= l.retryAt.Load() {
+		res, err := l.primary.Allow(ctx, key, limit)
+		if err == nil {
+			return res, nil
+		}
+		l.retryAt.Store(now + int64(primaryRetryDelay))
+		if last := l.lastLog.Load(); now-last > int64(fallbackLogInterval) && l.lastLog.CompareAndSwap(last, now) {
+			slog.WarnContext(ctx, "ratelimit: primary limiter failed, using in-process limits", "error", err)
+		}
+	}
+	return l.secondary.Allow(ctx, key, limit)
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware limits requests per route and caller. Callers are identified by
+// user, API key or, without credentials, client IP, so it must run after
+// auth.Authenticate. Limiter errors let the request through.
+func Middleware(limiter Limiter, settings *Settings) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.FullPath() == "" || !settings.enabled.Load() {
+			c.Next()
+			return
+		}
+		route := c.Request.Method + " " + c.FullPath()
+		kind, id := identify(c)
+		limit, ok := settings.rules.Load().For(route, kind)
+		if !ok {
+			c.Next()
+			return
+		}
+		res, err := limiter.Allow(c.Request.Context(), "ratelimit:"+route+":"+kind+":"+id, limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "ratelimit: failed to check limit", "route", route, "identity", kind, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", seconds(res.Reset))
+		c.Header("RateLimit-Policy", limit.String())
+		if !res.Allowed {
+			c.Header("Retry-After", seconds(res.RetryAfter))
+			problem.Abort(c, problem.TypeRateLimited, "Rate limit exceeded")
+			return
+		}
+		c.Next()
+	}
+}
+
+func identify(c *gin.Context) (string, string) {
+	principal := auth.GetPrincipal(c)
+	switch {
+	case principal == nil:
+		return IdentityIP, c.ClientIP()
+	case principal.Kind == auth.KindAPIKey:
+		// Keys of one company share a principal ID; limit each key.
+		return IdentityAPIKey, principal.KeyID
+	}
+	return IdentityUser, principal.ID
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
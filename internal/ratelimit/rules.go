@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	IdentityIP     = "ip"
+	IdentityUser   = "user"
+	IdentityAPIKey = "api_key"
+)
+
+//go:embed default_limits.yaml
+var defaultRules []byte
+
+// Rules hold the limit for each identity kind, per route ("GET
+// /api/v1/jobs/search") with a fallback default. Routes and identities
+// without a limit are not limited.
+type Rules struct {
+	Default map[string]Limit
+	Routes  map[string]map[string]Limit
+}
+
+type rulesFile struct {
+	Default map[string]string            `yaml:"default"`
+	Routes  map[string]map[string]string `yaml:"routes"`
+}
+
+// LoadRules reads rules from a YAML file, or returns the built-in rules
+// when path is empty.
+func LoadRules(path string) (*Rules, error) {
+	data := defaultRules
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read rate limits: %w", err)
+		}
+	}
+	return ParseRules(data)
+}
+
+func ParseRules(data []byte) (*Rules, error) {
+	var file rulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse rate limits: %w", err)
+	}
+	rules := &Rules{Routes: make(map[string]map[string]Limit)}
+	var err error
+	if rules.Default, err = parseLimitSet(file.Default); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	for route, set := range file.Routes {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid route %q, want \"METHOD /path\"", route)
+		}
+		if rules.Routes[route], err = parseLimitSet(set); err != nil {
+			return nil, fmt.Errorf("%s: %w", route, err)
+		}
+	}
+	return rules, nil
+}
+
+func parseLimitSet(raw map[string]string) (map[string]Limit, error) {
+	set := make(map[string]Limit, len(raw))
+	for identity, value := range raw {
+		switch identity {
+		case IdentityIP, IdentityUser, IdentityAPIKey:
+		default:
+			return nil, fmt.Errorf("unknown identity %q", identity)
+		}
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return nil, err
+		}
+		set[identity] = limit
+	}
+	return set, nil
+}
+
+// For returns the limit for an identity kind on a route.
+func (r *Rules) For(route, identity string) (Limit, bool) {
+	if limit, ok := r.Routes[route][identity]; ok {
+		return limit, true
+	}
+	limit, ok := r.Default[identity]
+	return limit, ok
+}
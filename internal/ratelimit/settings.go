@@ -0,0 +1,21 @@
+package ratelimit
+
+import "sync/atomic"
+
+// Settings hold the switch and rules the middleware reads on every request,
+// so that a configuration reload can swap them while serving.
+type Settings struct {
+	enabled atomic.Bool
+	rules   atomic.Pointer[Rules]
+}
+
+func NewSettings(enabled bool, rules *Rules) *Settings {
+	s := &Settings{}
+	s.Set(enabled, rules)
+	return s
+}
+
+func (s *Settings) Set(enabled bool, rules *Rules) {
+	s.rules.Store(rules)
+	s.enabled.Store(enabled)
+}
@@ -1,29 +1,192 @@
 package internal
 
 import (
+	"net/http"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/health"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/logging"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/ratelimit"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/tracing"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/alerts"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/apikeys"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/applications"
 	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/users"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/webhooks"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(jobHandler *jobs.JobHandler) *gin.Engine {
-	r := gin.Default()
+type Dependencies struct {
+	JobHandler         *jobs.JobHandler
+	ApplicationHandler *applications.ApplicationHandler
+	SavedSearchHandler *alerts.SavedSearchHandler
+	WebhookHandler     *webhooks.WebhookHandler
+	APIKeyHandler      *apikeys.APIKeyHandler
+	// AuthHandler serves account registration and login; nil disables it.
+	AuthHandler *users.AuthHandler
+
+	// Verifier enables JWT authentication; nil disables it.
+	Verifier *auth.Verifier
+	// APIKeys validates X-API-Key credentials when authentication is on.
+	APIKeys auth.APIKeyValidator
+	// Sessions rejects tokens whose login session has been revoked.
+	Sessions auth.SessionValidator
+	// ProtectReads also requires a token for GET requests.
+	ProtectReads bool
+	// Policy is the RBAC matrix enforced on job routes when authentication
+	// is on.
+	Policy *auth.Policy
+
+	// RateLimiter and RateLimits enable per-route rate limiting when both
+	// are set; RateLimits can switch it off at runtime.
+	RateLimiter ratelimit.Limiter
+	RateLimits  *ratelimit.Settings
+	// Health serves /healthz and /readyz; nil disables them.
+	Health *health.Handler
+	// TrustedProxies may set the client IP with X-Forwarded-For; with none,
+	// the client IP is the connection's address. Rate limits and the login
+	// throttle key on it.
+	TrustedProxies []string
+}
+
+func SetupRouter(deps Dependencies) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(deps.TrustedProxies); err != nil {
+		return nil, err
+	}
+	r.Use(logging.RequestID(), tracing.Middleware("/metrics", "/healthz", "/readyz"), logging.AccessLog(), logging.Recovery())
+	// Both check metrics.SetEnabled on every request.
+	r.Use(metrics.Middleware())
+	r.GET("/metrics", metrics.Handler())
+	if deps.Health != nil {
+		r.GET("/healthz", deps.Health.Live)
+		r.GET("/readyz", deps.Health.Ready)
+	}
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	authEnabled := deps.Verifier != nil
+	authenticate := auth.Authenticate(deps.Verifier, deps.APIKeys, deps.Sessions)
+	var limit []gin.HandlerFunc
+	if deps.RateLimiter != nil && deps.RateLimits != nil {
+		limit = append(limit, ratelimit.Middleware(deps.RateLimiter, deps.RateLimits))
+	}
+
+	// Account endpoints sit outside requireToken: registering, logging in
+	// and refreshing happen without an access token.
+	if authEnabled && deps.AuthHandler != nil {
+		accountGroup := r.Group("/api/v1/auth", authenticate)
+		accountGroup.Use(limit...)
+		{
+			accountGroup.POST("/register", deps.AuthHandler.Register)
+			accountGroup.POST("/login", deps.AuthHandler.Login)
+			accountGroup.POST("/refresh", deps.AuthHandler.Refresh)
+			accountGroup.POST("/logout", deps.AuthHandler.Logout)
+		}
+	}
+
 	api := r.Group("/api/v1")
+	if authEnabled {
+		api.Use(authenticate, requireToken(deps.ProtectReads))
+	}
+	api.Use(limit...)
 	{
+		jobHandler := deps.JobHandler
+		applicationHandler := deps.ApplicationHandler
+		savedSearchHandler := deps.SavedSearchHandler
+		webhookHandler := deps.WebhookHandler
+		apiKeyHandler := deps.APIKeyHandler
+
+		can := func(action string) gin.HandlerFunc {
+			if !authEnabled || deps.Policy == nil {
+				return func(c *gin.Context) { c.Next() }
+			}
+			return auth.Authorize(deps.Policy, action)
+		}
+		// requireAuth guards routes that are never public, even when
+		// other reads are.
+		requireAuth := func(c *gin.Context) { c.Next() }
+		if authEnabled {
+			requireAuth = auth.Require()
+		}
+
 		jobsGroup := api.Group("/jobs")
 		{
-			jobsGroup.POST("", jobHandler.CreateJob)
-			jobsGroup.GET("", jobHandler.ListJobs)
-			jobsGroup.GET(":id", jobHandler.GetJobByID)
-			jobsGroup.PUT(":id", jobHandler.UpdateJob)
-			jobsGroup.DELETE(":id", jobHandler.DeleteJob)
-			jobsGroup.GET("/search", jobHandler.SearchJobs)
+			jobsGroup.POST("", can(jobs.ActionCreate), jobHandler.CreateJob)
+			jobsGroup.GET("", can(jobs.ActionRead), jobHandler.ListJobs)
+			jobsGroup.GET(":id", can(jobs.ActionRead), jobHandler.GetJobByID)
+			jobsGroup.PUT(":id", can(jobs.ActionUpdate), jobHandler.UpdateJob)
+			jobsGroup.DELETE(":id", can(jobs.ActionDelete), jobHandler.DeleteJob)
+			jobsGroup.GET("/search", can(jobs.ActionRead), jobHandler.SearchJobs)
+			jobsGroup.GET(":id/translations", can(jobs.ActionRead), jobHandler.ListTranslations)
+			jobsGroup.GET(":id/translations/:lang", can(jobs.ActionRead), jobHandler.GetTranslation)
+			jobsGroup.PUT(":id/translations/:lang", can(jobs.ActionUpdate), jobHandler.PutTranslation)
+			jobsGroup.DELETE(":id/translations/:lang", can(jobs.ActionUpdate), jobHandler.DeleteTranslation)
+			jobsGroup.POST(":id/applications", applicationHandler.CreateApplication)
+			jobsGroup.GET(":id/applications", requireAuth, applicationHandler.ListApplications)
+			jobsGroup.GET(":id/applications/stages", requireAuth, applicationHandler.GetStageCounts)
+		}
+
+		applicationsGroup := api.Group("/applications", requireAuth)
+		{
+			applicationsGroup.GET(":id", applicationHandler.GetApplication)
+			applicationsGroup.POST(":id/transition", applicationHandler.TransitionApplication)
+		}
+
+		pipelinesGroup := api.Group("/pipelines", requireAuth)
+		{
+			pipelinesGroup.GET(":company", applicationHandler.GetPipeline)
+			pipelinesGroup.PUT(":company", applicationHandler.SavePipeline)
+		}
+
+		savedSearchesGroup := api.Group("/saved-searches", requireAuth)
+		{
+			savedSearchesGroup.POST("", savedSearchHandler.CreateSavedSearch)
+			savedSearchesGroup.GET("", savedSearchHandler.ListSavedSearches)
+			savedSearchesGroup.GET(":id", savedSearchHandler.GetSavedSearch)
+			savedSearchesGroup.DELETE(":id", savedSearchHandler.DeleteSavedSearch)
+		}
+
+		webhooksGroup := api.Group("/webhooks", requireAuth)
+		{
+			webhooksGroup.POST("", webhookHandler.CreateSubscription)
+			webhooksGroup.GET("", webhookHandler.ListSubscriptions)
+			webhooksGroup.GET("/dead-letters", webhookHandler.ListDeadLetters)
+			webhooksGroup.GET("/deliveries/:id", webhookHandler.GetDelivery)
+			webhooksGroup.POST("/deliveries/:id/retry", webhookHandler.RetryDelivery)
+			webhooksGroup.GET(":id", webhookHandler.GetSubscription)
+			webhooksGroup.DELETE(":id", webhookHandler.DeleteSubscription)
+			webhooksGroup.GET(":id/deliveries", webhookHandler.ListDeliveries)
+		}
+
+		// Key metadata is never public either.
+		apiKeysGroup := api.Group("/companies/:company/api-keys", requireAuth)
+		{
+			apiKeysGroup.POST("", apiKeyHandler.CreateAPIKey)
+			apiKeysGroup.GET("", apiKeyHandler.ListAPIKeys)
+			apiKeysGroup.DELETE(":id", apiKeyHandler.RevokeAPIKey)
 		}
 	}
 
-	return r
+	return r, nil
+}
+
+// requireToken makes POST, PUT and DELETE (and any other non-read method)
+// require a valid bearer token or API key. Reads stay public unless protectReads is set.
+func requireToken(protectReads bool) gin.HandlerFunc {
+	require := auth.Require()
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if !protectReads {
+				c.Next()
+				return
+			}
+		}
+		require(c)
+	}
 }
@@ -0,0 +1,130 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+)
+
+const (
+	instantQueueSize = 256
+	digestInterval   = 10 * time.Minute
+)
+
+// Alerter matches job events against saved searches as the outbox relay
+// publishes them and delivers the matches through a Notifier, either
+// immediately or batched into daily and weekly digests.
+type Alerter struct {
+	repo     SavedSearchRepository
+	notifier Notifier
+	instant  chan SavedSearch
+}
+
+func NewAlerter(repo SavedSearchRepository, notifier Notifier) *Alerter {
+	return &Alerter{
+		repo:     repo,
+		notifier: notifier,
+		instant:  make(chan SavedSearch, instantQueueSize),
+	}
+}
+
+// Publish matches a job event against the saved searches and stores the
+// matches before it returns, so the outbox only marks the event processed
+// once its alerts are persisted. An error makes the relay retry the event;
+// matches already recorded are not recorded twice. Instant alerts are
+// handed to Run for sending.
+func (a *Alerter) Publish(ctx context.Context, event jobs.JobEvent) error {
+	if event.Type != jobs.EventJobCreated && event.Type != jobs.EventJobUpdated {
+		return nil
+	}
+	return a.match(ctx, event.Job)
+}
+
+func (a *Alerter) Run(ctx context.Context) {
+	ticker := time.NewTicker(digestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case search := <-a.instant:
+			a.deliver(ctx, search)
+		case <-ticker.C:
+			// Instant alerts that failed to send, or were not handed over
+			// because the queue was full, are sent on every tick.
+			a.sendDigests(ctx, FrequencyInstant, 0)
+			a.sendDigests(ctx, FrequencyDaily, 24*time.Hour)
+			a.sendDigests(ctx, FrequencyWeekly, 7*24*time.Hour)
+		}
+	}
+}
+
+func (a *Alerter) match(ctx context.Context, job jobs.Job) error {
+	if !job.Status {
+		return nil
+	}
+	candidates, err := a.repo.Candidates(ctx, job)
+	if err != nil {
+		return fmt.Errorf("alerts: load saved searches: %w", err)
+	}
+	now := time.Now().Unix()
+	var errs []error
+	for _, search := range candidates {
+		if !Matches(search, job) {
+			continue
+		}
+		created, err := a.repo.RecordMatch(ctx, search.ID, job.ID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alerts: record match of search %d: %w", search.ID, err))
+			continue
+		}
+		if created {
+			metrics.AlertMatches.Inc()
+		}
+		if created && search.Frequency == FrequencyInstant {
+			select {
+			case a.instant <- search:
+			default:
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Alerter) sendDigests(ctx context.Context, frequency string, period time.Duration) {
+	due, err := a.repo.DueDigests(ctx, frequency, time.Now().Add(-period).Unix())
+	if err != nil {
+		slog.ErrorContext(ctx, "alerts: failed to load due digests", "frequency", frequency, "error", err)
+		return
+	}
+	for _, search := range due {
+		a.deliver(ctx, search)
+	}
+}
+
+func (a *Alerter) deliver(ctx context.Context, search SavedSearch) {
+	pending, matchIDs, err := a.repo.PendingJobs(ctx, search.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "alerts: failed to load pending matches", "search_id", search.ID, "error", err)
+		return
+	}
+	now := time.Now().Unix()
+	if len(pending) > 0 {
+		digest := Digest{Search: search, Jobs: pending, SentAt: now}
+		if err := a.notifier.Notify(ctx, digest); err != nil {
+			// Matches stay pending and are retried with the next digest run.
+			metrics.AlertNotifications.WithLabelValues(search.Frequency, "failed").Inc()
+			slog.ErrorContext(ctx, "alerts: failed to notify", "email", search.Email, "search_id", search.ID, "error", err)
+			return
+		}
+		metrics.AlertNotifications.WithLabelValues(search.Frequency, "sent").Inc()
+	}
+	if err := a.repo.MarkDelivered(ctx, search.ID, matchIDs, now); err != nil {
+		slog.ErrorContext(ctx, "alerts: failed to mark search delivered", "search_id", search.ID, "error", err)
+	}
+}
@@ -0,0 +1,25 @@
+package alerts
+
+type CreateSavedSearchRequest struct {
+	Email     string `json:"email" binding:"required,email,max=255"`
+	Name      string `json:"name" binding:"required,max=255,singleline"`
+	Query     string `json:"query" binding:"max=255,singleline"`
+	Company   string `json:"company" binding:"max=255"`
+	City      string `json:"city" binding:"max=100"`
+	State     string `json:"state" binding:"max=100"`
+	Frequency string `json:"frequency" binding:"omitempty,oneof=instant daily weekly"`
+}
+
+type SavedSearchResponse struct {
+	ID             uint   `json:"id"`
+	OwnerID        string `json:"owner_id"`
+	Email          string `json:"email"`
+	Name           string `json:"name"`
+	Query          string `json:"query"`
+	Company        string `json:"company"`
+	City           string `json:"city"`
+	State          string `json:"state"`
+	Frequency      string `json:"frequency"`
+	LastNotifiedAt int64  `json:"last_notified_at"`
+	CreatedAt      int64  `json:"created_at"`
+}
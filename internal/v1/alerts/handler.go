@@ -0,0 +1,188 @@
+package alerts
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+type SavedSearchHandler struct {
+	repo SavedSearchRepository
+}
+
+func NewSavedSearchHandler(repo SavedSearchRepository) *SavedSearchHandler {
+	return &SavedSearchHandler{repo: repo}
+}
+
+// CreateSavedSearch godoc
+// @Summary      Save a search
+// @Description  Save a job search query with filters and get alerted about new matching jobs
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Param        search  body  CreateSavedSearchRequest  true  "Saved search"
+// @Success      201  {object}  SavedSearchResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /saved-searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req CreateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	if req.Query == "" && req.Company == "" && req.City == "" && req.State == "" {
+		problem.Abort(c, problem.TypeBadRequest, "A query or at least one filter is required")
+		return
+	}
+	if req.Frequency == "" {
+		req.Frequency = FrequencyDaily
+	}
+	now := time.Now().Unix()
+	search := SavedSearch{
+		OwnerID:        ownerID(c),
+		Email:          req.Email,
+		Name:           req.Name,
+		Query:          req.Query,
+		Company:        req.Company,
+		City:           req.City,
+		State:          req.State,
+		Frequency:      req.Frequency,
+		LastNotifiedAt: now,
+		CreatedAt:      now,
+	}
+	if err := h.repo.Create(c.Request.Context(), &search); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toSavedSearchResponse(&search))
+}
+
+// ListSavedSearches godoc
+// @Summary      List saved searches
+// @Description  Get the caller's saved searches. Admins, and callers when authentication is disabled, list those of an email address instead.
+// @Tags         saved-searches
+// @Produce      json
+// @Param        email  query  string  false  "Alert email (admins only)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /saved-searches [get]
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	var (
+		searches []SavedSearch
+		err      error
+	)
+	principal := auth.GetPrincipal(c)
+	if email := c.Query("email"); principal == nil || (principal.HasRole("admin") && email != "") {
+		if email == "" {
+			problem.Abort(c, problem.TypeBadRequest, "Missing email")
+			return
+		}
+		searches, err = h.repo.ListByEmail(c.Request.Context(), email)
+	} else {
+		searches, err = h.repo.ListByOwner(c.Request.Context(), principal.ID)
+	}
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	responses := make([]SavedSearchResponse, len(searches))
+	for i := range searches {
+		responses[i] = toSavedSearchResponse(&searches[i])
+	}
+	c.JSON(http.StatusOK, gin.H{"saved_searches": responses})
+}
+
+// GetSavedSearch godoc
+// @Summary      Get a saved search
+// @Tags         saved-searches
+// @Produce      json
+// @Param        id   path  int  true  "Saved search ID"
+// @Success      200  {object}  SavedSearchResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /saved-searches/{id} [get]
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	search, ok := h.load(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, toSavedSearchResponse(search))
+}
+
+// DeleteSavedSearch godoc
+// @Summary      Delete a saved search
+// @Tags         saved-searches
+// @Param        id   path  int  true  "Saved search ID"
+// @Success      204  {string}  string  ""
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /saved-searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	search, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(c.Request.Context(), search.ID); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// load returns the saved search named in the path if the caller owns it
+// or is an admin, and aborts the request otherwise. With authentication
+// disabled there is no principal and every request passes.
+func (h *SavedSearchHandler) load(c *gin.Context) (*SavedSearch, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		problem.Abort(c, problem.TypeBadRequest, "Invalid saved search id")
+		return nil, false
+	}
+	search, err := h.repo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		problem.Error(c, err)
+		return nil, false
+	}
+	if principal := auth.GetPrincipal(c); principal != nil && !principal.HasRole("admin") && !principal.Owns(search.OwnerID) {
+		problem.Abort(c, problem.TypeForbidden, "Not allowed to access this saved search")
+		return nil, false
+	}
+	return search, true
+}
+
+func ownerID(c *gin.Context) string {
+	if principal := auth.GetPrincipal(c); principal != nil {
+		return principal.ID
+	}
+	return ""
+}
+
+func toSavedSearchResponse(search *SavedSearch) SavedSearchResponse {
+	return SavedSearchResponse{
+		ID:             search.ID,
+		OwnerID:        search.OwnerID,
+		Email:          search.Email,
+		Name:           search.Name,
+		Query:          search.Query,
+		Company:        search.Company,
+		City:           search.City,
+		State:          search.State,
+		Frequency:      search.Frequency,
+		LastNotifiedAt: search.LastNotifiedAt,
+		CreatedAt:      search.CreatedAt,
+	}
+}
@@ -0,0 +1,41 @@
+package alerts
+
+import (
+	"strings"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+)
+
+// Matches reports whether job would be returned by the saved search. The
+// query uses the same semantics as /jobs/search (a case-insensitive substring
+// of title, description, company, city or state, or of the title or
+// description of a translation) and every non-empty filter must match
+// exactly, ignoring case. Inactive jobs never match.
+func Matches(search SavedSearch, job jobs.Job) bool {
+	if !job.Status {
+		return false
+	}
+	if search.Company != "" && !strings.EqualFold(search.Company, job.Company) {
+		return false
+	}
+	if search.City != "" && !strings.EqualFold(search.City, job.City) {
+		return false
+	}
+	if search.State != "" && !strings.EqualFold(search.State, job.State) {
+		return false
+	}
+	if search.Query == "" {
+		return true
+	}
+	q := strings.ToLower(search.Query)
+	fields := []string{job.Title, job.Description, job.Company, job.City, job.State}
+	for _, t := range job.Translations {
+		fields = append(fields, t.Title, t.Description)
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), q) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package alerts
+
+const (
+	FrequencyInstant = "instant"
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+)
+
+type SavedSearch struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	OwnerID        string `gorm:"size:255;index" json:"owner_id"`
+	Email          string `gorm:"size:255;index" json:"email"`
+	Name           string `json:"name"`
+	Query          string `json:"query"`
+	Company        string `gorm:"size:255" json:"company"`
+	City           string `gorm:"size:100" json:"city"`
+	State          string `gorm:"size:100" json:"state"`
+	Frequency      string `gorm:"size:16;index" json:"frequency"`
+	LastNotifiedAt int64  `json:"last_notified_at"`
+	CreatedAt      int64  `json:"created_at"`
+}
+
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// AlertMatch records that a job matched a saved search. A job is matched at
+// most once per search, so later updates to the same job do not re-alert.
+type AlertMatch struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	SavedSearchID uint   `gorm:"uniqueIndex:idx_alert_match" json:"saved_search_id"`
+	JobID         uint   `gorm:"uniqueIndex:idx_alert_match" json:"job_id"`
+	MatchedAt     int64  `json:"matched_at"`
+	DeliveredAt   *int64 `gorm:"index" json:"delivered_at"`
+}
+
+func (AlertMatch) TableName() string {
+	return "alert_matches"
+}
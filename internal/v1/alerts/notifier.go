@@ -0,0 +1,156 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+)
+
+// Digest is one delivery to a saved search's owner: a single job for
+// instant alerts, or everything matched since the last daily/weekly digest.
+type Digest struct {
+	Search SavedSearch `json:"search"`
+	Jobs   []jobs.Job  `json:"jobs"`
+	SentAt int64       `json:"sent_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, digest Digest) error
+}
+
+type NotifierConfig struct {
+	Kind         string
+	FilePath     string
+	WebhookURL   string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+}
+
+func NewNotifier(cfg NotifierConfig) (Notifier, error) {
+	switch cfg.Kind {
+	case "", "log":
+		return &LogNotifier{}, nil
+	case "file":
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("file notifier requires a file path")
+		}
+		f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		return &LogNotifier{out: f}, nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("webhook notifier requires a URL")
+		}
+		return &WebhookNotifier{url: cfg.WebhookURL, client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "smtp":
+		if cfg.SMTPAddr == "" || cfg.SMTPFrom == "" {
+			return nil, fmt.Errorf("smtp notifier requires an address and a from address")
+		}
+		return &SMTPNotifier{addr: cfg.SMTPAddr, username: cfg.SMTPUsername, password: cfg.SMTPPassword, from: cfg.SMTPFrom}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", cfg.Kind)
+	}
+}
+
+// LogNotifier writes each digest as a JSON line to out, or to the service
+// log when out is nil. It is meant for local runs where no mail server or
+// webhook receiver is available.
+type LogNotifier struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, digest Digest) error {
+	line, err := json.Marshal(digest)
+	if err != nil {
+		return err
+	}
+	if n.out == nil {
+		slog.InfoContext(ctx, "alerts: digest", "digest", json.RawMessage(line))
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.out.Write(append(line, '\n'))
+	return err
+}
+
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, digest Digest) error {
+	body, err := json.Marshal(digest)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type SMTPNotifier struct {
+	addr     string
+	username string
+	password string
+	from     string
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, digest Digest) error {
+	var auth smtp.Auth
+	if n.username != "" {
+		host := n.addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", n.username, n.password, host)
+	}
+	return smtp.SendMail(n.addr, auth, n.from, []string{digest.Search.Email}, formatEmail(n.from, digest))
+}
+
+func formatEmail(from string, digest Digest) []byte {
+	name := digest.Search.Name
+	if name == "" {
+		name = digest.Search.Query
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", digest.Search.Email)
+	// The name is the user's; encoding it keeps line breaks or non-ASCII
+	// text from changing the headers.
+	subject := fmt.Sprintf("%d new job(s) for \"%s\"", len(digest.Jobs), name)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	for _, job := range digest.Jobs {
+		fmt.Fprintf(&b, "%s at %s (%s, %s)\r\n", job.Title, job.Company, job.City, job.State)
+	}
+	return []byte(b.String())
+}
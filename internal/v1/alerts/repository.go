@@ -0,0 +1,148 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *SavedSearch) error
+	GetByID(ctx context.Context, id uint) (*SavedSearch, error)
+	ListByEmail(ctx context.Context, email string) ([]SavedSearch, error)
+	ListByOwner(ctx context.Context, ownerID string) ([]SavedSearch, error)
+	Delete(ctx context.Context, id uint) error
+	Candidates(ctx context.Context, job jobs.Job) ([]SavedSearch, error)
+	RecordMatch(ctx context.Context, searchID, jobID uint, matchedAt int64) (bool, error)
+	DueDigests(ctx context.Context, frequency string, notifiedBefore int64) ([]SavedSearch, error)
+	PendingJobs(ctx context.Context, searchID uint) ([]jobs.Job, []uint, error)
+	MarkDelivered(ctx context.Context, searchID uint, matchIDs []uint, deliveredAt int64) error
+}
+
+type GormSavedSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSavedSearchRepository(db *gorm.DB) SavedSearchRepository {
+	return &GormSavedSearchRepository{db: db}
+}
+
+func (r *GormSavedSearchRepository) Create(ctx context.Context, search *SavedSearch) error {
+	return r.db.WithContext(ctx).Create(search).Error
+}
+
+func (r *GormSavedSearchRepository) GetByID(ctx context.Context, id uint) (*SavedSearch, error) {
+	var search SavedSearch
+	if err := r.db.WithContext(ctx).First(&search, id).Error; err != nil {
+		return nil, apperr.FromDB(err, "Saved search")
+	}
+	return &search, nil
+}
+
+func (r *GormSavedSearchRepository) ListByEmail(ctx context.Context, email string) ([]SavedSearch, error) {
+	var searches []SavedSearch
+	err := r.db.WithContext(ctx).Where("email = ?", email).Order("created_at desc").Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+func (r *GormSavedSearchRepository) ListByOwner(ctx context.Context, ownerID string) ([]SavedSearch, error) {
+	var searches []SavedSearch
+	err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("created_at desc").Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+func (r *GormSavedSearchRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("saved_search_id = ?", id).Delete(&AlertMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&SavedSearch{}, id).Error
+	})
+}
+
+// Candidates narrows the saved searches to those whose filters can match the
+// job; the query itself is checked in Go by Matches.
+func (r *GormSavedSearchRepository) Candidates(ctx context.Context, job jobs.Job) ([]SavedSearch, error) {
+	var searches []SavedSearch
+	err := r.db.WithContext(ctx).
+		Where("company = '' OR company = ?", job.Company).
+		Where("city = '' OR city = ?", job.City).
+		Where("state = '' OR state = ?", job.State).
+		Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// RecordMatch stores a match and reports whether it is new.
+func (r *GormSavedSearchRepository) RecordMatch(ctx context.Context, searchID, jobID uint, matchedAt int64) (bool, error) {
+	match := AlertMatch{SavedSearchID: searchID, JobID: jobID, MatchedAt: matchedAt}
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&match)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *GormSavedSearchRepository) DueDigests(ctx context.Context, frequency string, notifiedBefore int64) ([]SavedSearch, error) {
+	var searches []SavedSearch
+	err := r.db.WithContext(ctx).
+		Where("frequency = ? AND last_notified_at <= ?", frequency, notifiedBefore).
+		Where("EXISTS (SELECT 1 FROM alert_matches m WHERE m.saved_search_id = saved_searches.id AND m.delivered_at IS NULL)").
+		Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// PendingJobs returns the undelivered matched jobs of a search together with
+// the IDs of the matches. Matches whose job has since been deleted are
+// returned in the ID list so they get cleared on delivery.
+func (r *GormSavedSearchRepository) PendingJobs(ctx context.Context, searchID uint) ([]jobs.Job, []uint, error) {
+	var matches []AlertMatch
+	err := r.db.WithContext(ctx).
+		Where("saved_search_id = ? AND delivered_at IS NULL", searchID).
+		Order("matched_at asc").
+		Find(&matches).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil, nil
+	}
+	matchIDs := make([]uint, len(matches))
+	jobIDs := make([]uint, len(matches))
+	for i, m := range matches {
+		matchIDs[i] = m.ID
+		jobIDs[i] = m.JobID
+	}
+	var matched []jobs.Job
+	err = r.db.WithContext(ctx).Where("id IN ? AND status = ?", jobIDs, true).Order("created_at desc").Find(&matched).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return matched, matchIDs, nil
+}
+
+func (r *GormSavedSearchRepository) MarkDelivered(ctx context.Context, searchID uint, matchIDs []uint, deliveredAt int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(matchIDs) > 0 {
+			err := tx.Model(&AlertMatch{}).Where("id IN ?", matchIDs).Update("delivered_at", deliveredAt).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&SavedSearch{}).Where("id = ?", searchID).Update("last_notified_at", deliveredAt).Error
+	})
+}
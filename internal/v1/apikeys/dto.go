@@ -0,0 +1,18 @@
+package apikeys
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type APIKeyResponse struct {
+	ID         uint   `json:"id"`
+	Company    string `json:"company"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix"`
+	CreatedBy  string `json:"created_by"`
+	LastUsedAt *int64 `json:"last_used_at"`
+	RevokedAt  *int64 `json:"revoked_at"`
+	CreatedAt  int64  `json:"created_at"`
+	// Key is only returned when the key is created; it cannot be recovered.
+	Key string `json:"key,omitempty"`
+}
@@ -0,0 +1,141 @@
+package apikeys
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	repo    APIKeyRepository
+	service *Service
+}
+
+func NewAPIKeyHandler(repo APIKeyRepository, service *Service) *APIKeyHandler {
+	return &APIKeyHandler{repo: repo, service: service}
+}
+
+// CreateAPIKey godoc
+// @Summary      Issue an API key
+// @Description  Create an API key scoped to a company. The key is only returned in this response.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        company  path  string               true  "Company name"
+// @Param        key      body  CreateAPIKeyRequest  true  "Key info"
+// @Success      201  {object}  APIKeyResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /companies/{company}/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	company := c.Param("company")
+	principal, ok := h.authorize(c, company)
+	if !ok {
+		return
+	}
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	createdBy := ""
+	if principal != nil {
+		createdBy = principal.ID
+	}
+	key, plaintext, err := h.service.Issue(c.Request.Context(), company, req.Name, createdBy)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	resp := toAPIKeyResponse(key)
+	resp.Key = plaintext
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListAPIKeys godoc
+// @Summary      List API keys
+// @Description  Get a company's API keys, including revoked ones
+// @Tags         api-keys
+// @Produce      json
+// @Param        company  path  string  true  "Company name"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /companies/{company}/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	company := c.Param("company")
+	if _, ok := h.authorize(c, company); !ok {
+		return
+	}
+	keys, err := h.repo.ListByCompany(c.Request.Context(), company)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	responses := make([]APIKeyResponse, len(keys))
+	for i := range keys {
+		responses[i] = toAPIKeyResponse(&keys[i])
+	}
+	c.JSON(http.StatusOK, gin.H{"api_keys": responses})
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Tags         api-keys
+// @Param        company  path  string  true  "Company name"
+// @Param        id       path  int     true  "API key ID"
+// @Success      204  {string}  string  ""
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /companies/{company}/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	company := c.Param("company")
+	if _, ok := h.authorize(c, company); !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		problem.Abort(c, problem.TypeBadRequest, "Invalid API key id")
+		return
+	}
+	if err := h.repo.Revoke(c.Request.Context(), company, uint(id), time.Now().Unix()); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// authorize allows admins and users whose token names the company. API keys
+// cannot manage keys, so a leaked key cannot mint new ones. With
+// authentication disabled there is no principal and every request passes.
+func (h *APIKeyHandler) authorize(c *gin.Context, company string) (*auth.Principal, bool) {
+	principal := auth.GetPrincipal(c)
+	if principal == nil {
+		return nil, true
+	}
+	if principal.Kind == auth.KindUser && (principal.HasRole("admin") || principal.Company == company) {
+		return principal, true
+	}
+	problem.Abort(c, problem.TypeForbidden, "Not allowed to manage API keys of this company")
+	return nil, false
+}
+
+func toAPIKeyResponse(key *APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Company:    key.Company,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		CreatedBy:  key.CreatedBy,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package apikeys
+
+type APIKey struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Company    string `gorm:"size:255;index" json:"company"`
+	Name       string `json:"name"`
+	Prefix     string `gorm:"size:16;uniqueIndex" json:"prefix"`
+	Hash       string `gorm:"size:64" json:"-"`
+	CreatedBy  string `json:"created_by"`
+	LastUsedAt *int64 `json:"last_used_at"`
+	RevokedAt  *int64 `json:"revoked_at"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
@@ -0,0 +1,66 @@
+package apikeys
+
+import (
+	"context"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	ListByCompany(ctx context.Context, company string) ([]APIKey, error)
+	Revoke(ctx context.Context, company string, id uint, revokedAt int64) error
+	TouchLastUsed(ctx context.Context, id uint, usedAt, notAfter int64) error
+}
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+func (r *GormAPIKeyRepository) Create(ctx context.Context, key *APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *GormAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	var key APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *GormAPIKeyRepository) ListByCompany(ctx context.Context, company string) ([]APIKey, error) {
+	var keys []APIKey
+	err := r.db.WithContext(ctx).Where("company = ?", company).Order("created_at desc").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *GormAPIKeyRepository) Revoke(ctx context.Context, company string, id uint, revokedAt int64) error {
+	res := r.db.WithContext(ctx).Model(&APIKey{}).
+		Where("id = ? AND company = ? AND revoked_at IS NULL", id, company).
+		Update("revoked_at", revokedAt)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return apperr.NotFound("API key not found")
+	}
+	return nil
+}
+
+// TouchLastUsed records usage, skipping the write when the key was already
+// marked as used after notAfter.
+func (r *GormAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, usedAt, notAfter int64) error {
+	return r.db.WithContext(ctx).Model(&APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, notAfter).
+		Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,87 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"gorm.io/gorm"
+)
+
+const (
+	keyPrefix = "jpk"
+	// lastUsedResolution limits last-used tracking to one write per key per
+	// minute.
+	lastUsedResolution = time.Minute
+)
+
+// Service issues and validates API keys. Keys look like
+// "jpk_<prefix>_<secret>"; only the prefix and a SHA-256 hash of the whole
+// key are stored.
+type Service struct {
+	repo APIKeyRepository
+}
+
+func NewService(repo APIKeyRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Issue creates a key for company and returns the stored record together
+// with the plaintext key.
+func (s *Service) Issue(ctx context.Context, company, name, createdBy string) (*APIKey, string, error) {
+	prefix := randomHex(6)
+	plaintext := keyPrefix + "_" + prefix + "_" + randomHex(24)
+	key := &APIKey{
+		Company:   company,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hashKey(plaintext),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, plaintext, nil
+}
+
+func (s *Service) ValidateAPIKey(ctx context.Context, plaintext string) (*auth.Principal, error) {
+	parts := strings.Split(plaintext, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	key, err := s.repo.GetByPrefix(ctx, parts[1])
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil || subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashKey(plaintext))) != 1 {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if err := s.repo.TouchLastUsed(ctx, key.ID, now.Unix(), now.Add(-lastUsedResolution).Unix()); err != nil {
+		slog.WarnContext(ctx, "apikeys: failed to record last use", "key_id", key.ID, "error", err)
+	}
+	return auth.CompanyPrincipal(key.Company, key.Name, key.Prefix), nil
+}
+
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
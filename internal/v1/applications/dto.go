@@ -0,0 +1,55 @@
+package applications
+
+type CreateApplicationRequest struct {
+	CandidateName  string `json:"candidate_name" binding:"required"`
+	CandidateEmail string `json:"candidate_email" binding:"required,email"`
+	CoverLetter    string `json:"cover_letter"`
+}
+
+type TransitionRequest struct {
+	ToStage string `json:"to_stage" binding:"required"`
+	// Actor is only used when authentication is disabled; otherwise the
+	// caller's token subject or API key name is recorded.
+	Actor string `json:"actor"`
+	Note  string `json:"note"`
+}
+
+type ApplicationResponse struct {
+	ID             uint                    `json:"id"`
+	JobID          uint                    `json:"job_id"`
+	Company        string                  `json:"company"`
+	CandidateName  string                  `json:"candidate_name"`
+	CandidateEmail string                  `json:"candidate_email"`
+	CoverLetter    string                  `json:"cover_letter"`
+	Stage          string                  `json:"stage"`
+	CreatedAt      int64                   `json:"created_at"`
+	UpdatedAt      int64                   `json:"updated_at"`
+	Transitions    []ApplicationTransition `json:"transitions,omitempty"`
+}
+
+type PipelineStageRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Terminal bool   `json:"terminal"`
+}
+
+type PipelineTransitionRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+type SavePipelineRequest struct {
+	InitialStage string                      `json:"initial_stage" binding:"required"`
+	Stages       []PipelineStageRequest      `json:"stages" binding:"required,min=1,dive"`
+	Transitions  []PipelineTransitionRequest `json:"transitions" binding:"dive"`
+}
+
+type StageCount struct {
+	Stage string `json:"stage"`
+	Count int64  `json:"count"`
+}
+
+type StageCountsResponse struct {
+	JobID  uint         `json:"job_id"`
+	Total  int64        `json:"total"`
+	Stages []StageCount `json:"stages"`
+}
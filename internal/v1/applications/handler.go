@@ -0,0 +1,386 @@
+package applications
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+type ApplicationHandler struct {
+	repo  ApplicationRepository
+	jobs  jobs.JobRepository
+	pages pagination.Limits
+}
+
+func NewApplicationHandler(repo ApplicationRepository, jobRepo jobs.JobRepository, pages pagination.Limits) *ApplicationHandler {
+	return &ApplicationHandler{repo: repo, jobs: jobRepo, pages: pages}
+}
+
+// CreateApplication godoc
+// @Summary      Apply to a job
+// @Description  Submit an application; it starts in the initial stage of the company's pipeline
+// @Tags         applications
+// @Accept       json
+// @Produce      json
+// @Param        id           path  int                       true  "Job ID"
+// @Param        application  body  CreateApplicationRequest  true  "Application info"
+// @Success      201  {object}  ApplicationResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /jobs/{id}/applications [post]
+func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
+	jobID, ok := parseID(c, "id", "Invalid job id")
+	if !ok {
+		return
+	}
+	var req CreateApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	job, err := h.jobs.GetByID(c.Request.Context(), jobID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	if !job.Status {
+		problem.Abort(c, problem.TypeBadRequest, "Job is not accepting applications")
+		return
+	}
+	pipeline, err := h.repo.GetPipeline(c.Request.Context(), job.Company)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	now := time.Now().Unix()
+	app := Application{
+		JobID:          job.ID,
+		Company:        job.Company,
+		CandidateName:  req.CandidateName,
+		CandidateEmail: req.CandidateEmail,
+		CoverLetter:    req.CoverLetter,
+		Stage:          pipeline.InitialStage,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := h.repo.Create(c.Request.Context(), &app); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toApplicationResponse(&app, nil))
+}
+
+// ListApplications godoc
+// @Summary      List applications for a job
+// @Description  Get a job's applications with pagination
+// @Tags         applications
+// @Produce      json
+// @Param        id     path   int  true   "Job ID"
+// @Param        page   query  int  false  "Page number"
+// @Param        limit  query  int  false  "Page size"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /jobs/{id}/applications [get]
+func (h *ApplicationHandler) ListApplications(c *gin.Context) {
+	jobID, ok := parseID(c, "id", "Invalid job id")
+	if !ok {
+		return
+	}
+	job, err := h.jobs.GetByID(c.Request.Context(), jobID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	if !authorize(c, job.Company, job.OwnerID) {
+		return
+	}
+	page, limit := h.pages.Parse(c)
+	apps, total, err := h.repo.ListByJob(c.Request.Context(), job.ID, (page-1)*limit, limit)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	responses := make([]ApplicationResponse, len(apps))
+	for i := range apps {
+		responses[i] = toApplicationResponse(&apps[i], nil)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"applications": responses,
+		"total":        total,
+		"page":         page,
+		"limit":        limit,
+	})
+}
+
+// GetStageCounts godoc
+// @Summary      Application counts per stage
+// @Description  Get the number of applications in each pipeline stage for a job
+// @Tags         applications
+// @Produce      json
+// @Param        id   path  int  true  "Job ID"
+// @Success      200  {object}  StageCountsResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /jobs/{id}/applications/stages [get]
+func (h *ApplicationHandler) GetStageCounts(c *gin.Context) {
+	jobID, ok := parseID(c, "id", "Invalid job id")
+	if !ok {
+		return
+	}
+	job, err := h.jobs.GetByID(c.Request.Context(), jobID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	if !authorize(c, job.Company, job.OwnerID) {
+		return
+	}
+	pipeline, err := h.repo.GetPipeline(c.Request.Context(), job.Company)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	counts, err := h.repo.StageCounts(c.Request.Context(), job.ID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	resp := StageCountsResponse{JobID: job.ID, Stages: make([]StageCount, 0, len(pipeline.Stages))}
+	for _, s := range pipeline.Stages {
+		resp.Stages = append(resp.Stages, StageCount{Stage: s.Name, Count: counts[s.Name]})
+		resp.Total += counts[s.Name]
+		delete(counts, s.Name)
+	}
+	// Applications left in stages that were removed from the pipeline are
+	// still reported so the totals add up.
+	for stage, count := range counts {
+		resp.Stages = append(resp.Stages, StageCount{Stage: stage, Count: count})
+		resp.Total += count
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetApplication godoc
+// @Summary      Get an application
+// @Description  Get an application with its stage history
+// @Tags         applications
+// @Produce      json
+// @Param        id   path  int  true  "Application ID"
+// @Success      200  {object}  ApplicationResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /applications/{id} [get]
+func (h *ApplicationHandler) GetApplication(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid application id")
+	if !ok {
+		return
+	}
+	app, ok := h.loadApplication(c, id)
+	if !ok {
+		return
+	}
+	transitions, err := h.repo.ListTransitions(c.Request.Context(), app.ID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toApplicationResponse(app, transitions))
+}
+
+// TransitionApplication godoc
+// @Summary      Move an application to another stage
+// @Description  Transition an application; only transitions allowed by the company's pipeline are accepted
+// @Tags         applications
+// @Accept       json
+// @Produce      json
+// @Param        id          path  int                true  "Application ID"
+// @Param        transition  body  TransitionRequest  true  "Target stage"
+// @Success      200  {object}  ApplicationResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /applications/{id}/transition [post]
+func (h *ApplicationHandler) TransitionApplication(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid application id")
+	if !ok {
+		return
+	}
+	var req TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	actor := req.Actor
+	if principal := auth.GetPrincipal(c); principal != nil {
+		actor = principal.Subject
+		if principal.Kind == auth.KindAPIKey {
+			actor = principal.ID + "/" + principal.Subject
+		}
+	}
+	if actor == "" {
+		problem.Abort(c, problem.TypeBadRequest, "Missing actor")
+		return
+	}
+	if _, ok := h.loadApplication(c, id); !ok {
+		return
+	}
+	app, err := h.repo.Transition(c.Request.Context(), id, req.ToStage, actor, req.Note)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	transitions, err := h.repo.ListTransitions(c.Request.Context(), app.ID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toApplicationResponse(app, transitions))
+}
+
+// GetPipeline godoc
+// @Summary      Get a company's pipeline
+// @Description  Get the hiring stages and allowed transitions for a company (the default pipeline if none is defined)
+// @Tags         pipelines
+// @Produce      json
+// @Param        company  path  string  true  "Company name"
+// @Success      200  {object}  Pipeline
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /pipelines/{company} [get]
+func (h *ApplicationHandler) GetPipeline(c *gin.Context) {
+	company := c.Param("company")
+	if !authorize(c, company, "") {
+		return
+	}
+	pipeline, err := h.repo.GetPipeline(c.Request.Context(), company)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pipeline)
+}
+
+// SavePipeline godoc
+// @Summary      Define a company's pipeline
+// @Description  Replace the hiring stages and allowed transitions for a company
+// @Tags         pipelines
+// @Accept       json
+// @Produce      json
+// @Param        company   path  string               true  "Company name"
+// @Param        pipeline  body  SavePipelineRequest  true  "Pipeline definition"
+// @Success      200  {object}  Pipeline
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /pipelines/{company} [put]
+func (h *ApplicationHandler) SavePipeline(c *gin.Context) {
+	company := c.Param("company")
+	if !authorize(c, company, "") {
+		return
+	}
+	var req SavePipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	pipeline := Pipeline{
+		Company:      company,
+		InitialStage: req.InitialStage,
+	}
+	for _, s := range req.Stages {
+		pipeline.Stages = append(pipeline.Stages, PipelineStage{Name: s.Name, Terminal: s.Terminal})
+	}
+	for _, t := range req.Transitions {
+		pipeline.Transitions = append(pipeline.Transitions, PipelineTransition{FromStage: t.From, ToStage: t.To})
+	}
+	if err := h.repo.SavePipeline(c.Request.Context(), &pipeline); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pipeline)
+}
+
+// loadApplication returns the application if the caller may manage it, and
+// aborts the request otherwise.
+func (h *ApplicationHandler) loadApplication(c *gin.Context, id uint) (*Application, bool) {
+	app, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		problem.Error(c, err)
+		return nil, false
+	}
+	var ownerID string
+	if auth.GetPrincipal(c) != nil {
+		// Applications outlive their job; without it only the company
+		// and admins have access.
+		if job, err := h.jobs.GetByID(c.Request.Context(), app.JobID); err == nil {
+			ownerID = job.OwnerID
+		}
+	}
+	if !authorize(c, app.Company, ownerID) {
+		return nil, false
+	}
+	return app, true
+}
+
+// authorize allows admins, members of the company and the owner of the
+// job, as for changes to the job itself. Candidates' details are never
+// shown to anyone else. With authentication disabled there is no
+// principal and every request passes.
+func authorize(c *gin.Context, company, ownerID string) bool {
+	principal := auth.GetPrincipal(c)
+	if principal == nil {
+		return true
+	}
+	if principal.HasRole("admin") || (company != "" && principal.Company == company) || principal.Owns(ownerID) {
+		return true
+	}
+	auth.Forbid(c, "applications:manage", "Not allowed to manage applications of this company")
+	return false
+}
+
+func parseID(c *gin.Context, param, message string) (uint, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil || id < 1 {
+		problem.Abort(c, problem.TypeBadRequest, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func toApplicationResponse(app *Application, transitions []ApplicationTransition) ApplicationResponse {
+	return ApplicationResponse{
+		ID:             app.ID,
+		JobID:          app.JobID,
+		Company:        app.Company,
+		CandidateName:  app.CandidateName,
+		CandidateEmail: app.CandidateEmail,
+		CoverLetter:    app.CoverLetter,
+		Stage:          app.Stage,
+		CreatedAt:      app.CreatedAt,
+		UpdatedAt:      app.UpdatedAt,
+		Transitions:    transitions,
+	}
+}
@@ -0,0 +1,67 @@
+package applications
+
+type Application struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	JobID          uint   `gorm:"index" json:"job_id"`
+	Company        string `gorm:"size:255;index" json:"company"`
+	CandidateName  string `json:"candidate_name"`
+	CandidateEmail string `json:"candidate_email"`
+	CoverLetter    string `gorm:"type:text" json:"cover_letter"`
+	Stage          string `gorm:"size:64;index" json:"stage"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
+
+func (Application) TableName() string {
+	return "applications"
+}
+
+type ApplicationTransition struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	ApplicationID uint   `gorm:"index" json:"application_id"`
+	FromStage     string `gorm:"size:64" json:"from_stage"`
+	ToStage       string `gorm:"size:64" json:"to_stage"`
+	Actor         string `json:"actor"`
+	Note          string `gorm:"type:text" json:"note"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+func (ApplicationTransition) TableName() string {
+	return "application_transitions"
+}
+
+type Pipeline struct {
+	ID           uint                 `gorm:"primaryKey" json:"id"`
+	Company      string               `gorm:"size:255;uniqueIndex" json:"company"`
+	InitialStage string               `gorm:"size:64" json:"initial_stage"`
+	Stages       []PipelineStage      `gorm:"constraint:OnDelete:CASCADE" json:"stages"`
+	Transitions  []PipelineTransition `gorm:"constraint:OnDelete:CASCADE" json:"transitions"`
+	UpdatedAt    int64                `json:"updated_at"`
+}
+
+func (Pipeline) TableName() string {
+	return "pipelines"
+}
+
+type PipelineStage struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	PipelineID uint   `gorm:"index" json:"-"`
+	Name       string `gorm:"size:64" json:"name"`
+	Position   int    `json:"position"`
+	Terminal   bool   `json:"terminal"`
+}
+
+func (PipelineStage) TableName() string {
+	return "pipeline_stages"
+}
+
+type PipelineTransition struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	PipelineID uint   `gorm:"index" json:"-"`
+	FromStage  string `gorm:"size:64" json:"from"`
+	ToStage    string `gorm:"size:64" json:"to"`
+}
+
+func (PipelineTransition) TableName() string {
+	return "pipeline_transitions"
+}
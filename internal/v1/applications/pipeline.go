@@ -0,0 +1,101 @@
+package applications
+
+import (
+	"strings"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+)
+
+func errTransitionNotAllowed(from, to string) error {
+	return apperr.Conflict("stage transition not allowed: %s -> %s", from, to)
+}
+
+func errInvalidPipeline(format string, args ...any) error {
+	return apperr.Validation("invalid pipeline: "+format, args...)
+}
+
+// DefaultPipeline is used for companies that have not defined their own
+// stages: applied → screened → interview → offer → hired, with rejection
+// possible from any open stage.
+func DefaultPipeline(company string) *Pipeline {
+	p := &Pipeline{
+		Company:      company,
+		InitialStage: "applied",
+		Stages: []PipelineStage{
+			{Name: "applied", Position: 0},
+			{Name: "screened", Position: 1},
+			{Name: "interview", Position: 2},
+			{Name: "offer", Position: 3},
+			{Name: "hired", Position: 4, Terminal: true},
+			{Name: "rejected", Position: 5, Terminal: true},
+		},
+		Transitions: []PipelineTransition{
+			{FromStage: "applied", ToStage: "screened"},
+			{FromStage: "screened", ToStage: "interview"},
+			{FromStage: "interview", ToStage: "offer"},
+			{FromStage: "offer", ToStage: "hired"},
+		},
+	}
+	for _, s := range p.Stages {
+		if !s.Terminal {
+			p.Transitions = append(p.Transitions, PipelineTransition{FromStage: s.Name, ToStage: "rejected"})
+		}
+	}
+	return p
+}
+
+func (p *Pipeline) HasStage(name string) bool {
+	for _, s := range p.Stages {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Pipeline) CanTransition(from, to string) bool {
+	for _, t := range p.Transitions {
+		if t.FromStage == from && t.ToStage == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that stage names are unique, the initial stage exists and
+// every transition references known stages. Terminal stages may not have
+// outgoing transitions.
+func (p *Pipeline) Validate() error {
+	if len(p.Stages) == 0 {
+		return errInvalidPipeline("at least one stage is required")
+	}
+	terminal := make(map[string]bool, len(p.Stages))
+	for _, s := range p.Stages {
+		if strings.TrimSpace(s.Name) == "" {
+			return errInvalidPipeline("stage name must not be empty")
+		}
+		if _, dup := terminal[s.Name]; dup {
+			return errInvalidPipeline("duplicate stage %q", s.Name)
+		}
+		terminal[s.Name] = s.Terminal
+	}
+	if !p.HasStage(p.InitialStage) {
+		return errInvalidPipeline("unknown initial stage %q", p.InitialStage)
+	}
+	for _, t := range p.Transitions {
+		isTerminal, ok := terminal[t.FromStage]
+		if !ok {
+			return errInvalidPipeline("unknown stage %q in transition", t.FromStage)
+		}
+		if !p.HasStage(t.ToStage) {
+			return errInvalidPipeline("unknown stage %q in transition", t.ToStage)
+		}
+		if isTerminal {
+			return errInvalidPipeline("terminal stage %q cannot transition", t.FromStage)
+		}
+		if t.FromStage == t.ToStage {
+			return errInvalidPipeline("stage %q cannot transition to itself", t.FromStage)
+		}
+	}
+	return nil
+}
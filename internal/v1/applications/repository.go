@@ -0,0 +1,184 @@
+package applications
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ApplicationRepository interface {
+	Create(ctx context.Context, app *Application) error
+	GetByID(ctx context.Context, id uint) (*Application, error)
+	ListByJob(ctx context.Context, jobID uint, offset, limit int) ([]Application, int64, error)
+	Transition(ctx context.Context, id uint, toStage, actor, note string) (*Application, error)
+	ListTransitions(ctx context.Context, applicationID uint) ([]ApplicationTransition, error)
+	StageCounts(ctx context.Context, jobID uint) (map[string]int64, error)
+	GetPipeline(ctx context.Context, company string) (*Pipeline, error)
+	SavePipeline(ctx context.Context, pipeline *Pipeline) error
+}
+
+type GormApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormApplicationRepository(db *gorm.DB) ApplicationRepository {
+	return &GormApplicationRepository{db: db}
+}
+
+func (r *GormApplicationRepository) Create(ctx context.Context, app *Application) error {
+	return r.db.WithContext(ctx).Create(app).Error
+}
+
+func (r *GormApplicationRepository) GetByID(ctx context.Context, id uint) (*Application, error) {
+	var app Application
+	if err := r.db.WithContext(ctx).First(&app, id).Error; err != nil {
+		return nil, apperr.FromDB(err, "Application")
+	}
+	return &app, nil
+}
+
+func (r *GormApplicationRepository) ListByJob(ctx context.Context, jobID uint, offset, limit int) ([]Application, int64, error) {
+	var apps []Application
+	var total int64
+	q := r.db.WithContext(ctx).Model(&Application{}).Where("job_id = ?", jobID)
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := q.Order("created_at desc").Offset(offset).Limit(limit).Find(&apps).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return apps, total, nil
+}
+
+// Transition moves an application to toStage if the company's pipeline
+// allows it. The application row is locked for the duration of the
+// transaction so concurrent transitions are applied one at a time.
+func (r *GormApplicationRepository) Transition(ctx context.Context, id uint, toStage, actor, note string) (*Application, error) {
+	var app Application
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&app, id).Error; err != nil {
+			return err
+		}
+		pipeline, err := loadPipeline(tx, app.Company)
+		if err != nil {
+			return err
+		}
+		if !pipeline.CanTransition(app.Stage, toStage) {
+			return errTransitionNotAllowed(app.Stage, toStage)
+		}
+
+		now := time.Now().Unix()
+		record := ApplicationTransition{
+			ApplicationID: app.ID,
+			FromStage:     app.Stage,
+			ToStage:       toStage,
+			Actor:         actor,
+			Note:          note,
+			CreatedAt:     now,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		app.Stage = toStage
+		app.UpdatedAt = now
+		return tx.Model(&Application{}).Where("id = ?", app.ID).Updates(map[string]interface{}{
+			"stage":      app.Stage,
+			"updated_at": app.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, apperr.FromDB(err, "Application")
+	}
+	return &app, nil
+}
+
+func (r *GormApplicationRepository) ListTransitions(ctx context.Context, applicationID uint) ([]ApplicationTransition, error) {
+	var transitions []ApplicationTransition
+	err := r.db.WithContext(ctx).
+		Where("application_id = ?", applicationID).
+		Order("created_at asc, id asc").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func (r *GormApplicationRepository) StageCounts(ctx context.Context, jobID uint) (map[string]int64, error) {
+	var rows []struct {
+		Stage string
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Model(&Application{}).
+		Select("stage, COUNT(*) AS count").
+		Where("job_id = ?", jobID).
+		Group("stage").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Stage] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *GormApplicationRepository) GetPipeline(ctx context.Context, company string) (*Pipeline, error) {
+	return loadPipeline(r.db.WithContext(ctx), company)
+}
+
+// SavePipeline replaces the company's pipeline definition, including all of
+// its stages and allowed transitions.
+func (r *GormApplicationRepository) SavePipeline(ctx context.Context, pipeline *Pipeline) error {
+	if err := pipeline.Validate(); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing Pipeline
+		err := tx.Where("company = ?", pipeline.Company).First(&existing).Error
+		switch {
+		case err == nil:
+			pipeline.ID = existing.ID
+			if err := tx.Where("pipeline_id = ?", existing.ID).Delete(&PipelineStage{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("pipeline_id = ?", existing.ID).Delete(&PipelineTransition{}).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			pipeline.ID = 0
+		default:
+			return err
+		}
+
+		for i := range pipeline.Stages {
+			pipeline.Stages[i].ID = 0
+			pipeline.Stages[i].Position = i
+		}
+		for i := range pipeline.Transitions {
+			pipeline.Transitions[i].ID = 0
+		}
+		pipeline.UpdatedAt = time.Now().Unix()
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(pipeline).Error
+	})
+}
+
+func loadPipeline(db *gorm.DB, company string) (*Pipeline, error) {
+	var pipeline Pipeline
+	err := db.Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).Preload("Transitions").Where("company = ?", company).First(&pipeline).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DefaultPipeline(company), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pipeline, nil
+}
@@ -1,22 +1,74 @@
 package db
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
+	"time"
 
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/logging"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/tracing"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
-func Connect(dsn string, models ...interface{}) *gorm.DB {
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+const slowQueryThreshold = 200 * time.Millisecond
+
+// PoolOptions sizes the connection pool; zero values keep database/sql's
+// defaults.
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// Connect opens the connection pool. The schema is managed by the
+// migrations package, not by the models.
+func Connect(dsn string, pool PoolOptions) *gorm.DB {
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: logging.NewGormLogger(slowQueryThreshold),
+	})
+	if err != nil {
+		slog.Error("failed to connect database", "error", err)
+		os.Exit(1)
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
+		slog.Error("failed to access connection pool", "error", err)
+		os.Exit(1)
+	}
+	sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		slog.Error("failed to register query metrics", "error", err)
+		os.Exit(1)
 	}
-	if len(models) > 0 {
-		err = db.AutoMigrate(models...)
-		if err != nil {
-			log.Fatalf("failed to migrate: %v", err)
-		}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		slog.Error("failed to register query tracing", "error", err)
+		os.Exit(1)
 	}
 	return db
 }
+
+// Ping checks that the connection pool can reach the database.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes the connection pool.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,282 @@
+// Package migrations applies the embedded, numbered SQL migrations that
+// define the database schema. Applied versions are recorded in the
+// schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockName serializes migrations across replicas with MySQL's GET_LOCK.
+const (
+	lockName           = "schema_migrations"
+	lockTimeoutSeconds = 60
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration together with when it was applied; AppliedAt is
+// nil for pending migrations.
+type Status struct {
+	Migration
+	AppliedAt *int64
+}
+
+// record is a row of schema_migrations.
+type record struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt int64
+}
+
+func (record) TableName() string {
+	return "schema_migrations"
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load reads NNNN_name.up.sql and NNNN_name.down.sql pairs from fsys in
+// version order. Every migration needs an up file; a missing down file
+// makes it irreversible.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		m := fileName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migrations: unexpected file %s", entry.Name())
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrations: %s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("migrations: version %d is used by both %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migrations: %04d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Status lists every known migration in version order.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration}
+		if rec, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &rec.AppliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Pending lists the migrations that have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return m.pending(applied), nil
+}
+
+// Up applies every pending migration in version order and returns the ones
+// it applied. MySQL commits DDL implicitly, so a migration that fails
+// halfway is left partially applied and unrecorded; its statements should
+// be safe to run again.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.pending(applied) {
+			if err := exec(conn, migration.Up); err != nil {
+				return fmt.Errorf("migrations: %04d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			rec := record{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().Unix()}
+			if err := conn.Create(&rec).Error; err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the latest steps applied migrations, newest first, and
+// returns the ones it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migrations: %04d_%s cannot be reverted", migration.Version, migration.Name)
+			}
+			if err := exec(conn, migration.Down); err != nil {
+				return fmt.Errorf("migrations: %04d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			if err := conn.Delete(&record{}, migration.Version).Error; err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *Migrator) pending(applied map[int64]record) []Migration {
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+func (m *Migrator) applied(db *gorm.DB) (map[int64]record, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+	var records []record
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at BIGINT NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`).Error
+}
+
+// locked runs fn on a single connection while holding the migration lock,
+// so that replicas starting together do not apply the same migration
+// twice. Migrations run on that connection too, which keeps session
+// variables and prepared statements working across statements.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		// A new session keeps the pinned connection without sharing
+		// clauses between the statements built on it.
+		conn = conn.Session(&gorm.Session{})
+		var acquired sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeoutSeconds).Row().Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("migrations: timed out waiting for the %s lock", lockName)
+		}
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+		return fn(conn)
+	})
+}
+
+func exec(conn *gorm.DB, script string) error {
+	for _, stmt := range split(script) {
+		if err := conn.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// split breaks a script into statements. A statement ends with a semicolon
+// at the end of a line; lines starting with "--" are comments.
+func split(script string) []string {
+	var stmts []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
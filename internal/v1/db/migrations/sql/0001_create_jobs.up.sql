@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    company VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100) NOT NULL,
+    status BOOLEAN DEFAULT TRUE,
+    created_at BIGINT NOT NULL,
+    owner_id VARCHAR(255),
+    INDEX idx_title (title),
+    INDEX idx_company (company),
+    INDEX idx_city (city),
+    INDEX idx_state (state),
+    INDEX idx_status (status),
+    INDEX idx_created_at (created_at),
+    INDEX idx_jobs_owner_id (owner_id),
+    FULLTEXT idx_search (title, description, company, city, state)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS pipeline_transitions;
+DROP TABLE IF EXISTS pipeline_stages;
+DROP TABLE IF EXISTS pipelines;
+DROP TABLE IF EXISTS application_transitions;
+DROP TABLE IF EXISTS applications;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS applications (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    job_id BIGINT UNSIGNED,
+    company VARCHAR(255),
+    candidate_name VARCHAR(255),
+    candidate_email VARCHAR(255),
+    cover_letter TEXT,
+    stage VARCHAR(64),
+    created_at BIGINT,
+    updated_at BIGINT,
+    INDEX idx_applications_job_id (job_id),
+    INDEX idx_applications_company (company),
+    INDEX idx_applications_stage (stage)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS application_transitions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    application_id BIGINT UNSIGNED,
+    from_stage VARCHAR(64),
+    to_stage VARCHAR(64),
+    actor VARCHAR(255),
+    note TEXT,
+    created_at BIGINT,
+    INDEX idx_application_transitions_application_id (application_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS pipelines (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    company VARCHAR(255),
+    initial_stage VARCHAR(64),
+    updated_at BIGINT,
+    UNIQUE INDEX idx_pipelines_company (company)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS pipeline_stages (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    pipeline_id BIGINT UNSIGNED,
+    name VARCHAR(64),
+    position BIGINT,
+    terminal BOOLEAN,
+    INDEX idx_pipeline_stages_pipeline_id (pipeline_id),
+    CONSTRAINT fk_pipelines_stages FOREIGN KEY (pipeline_id) REFERENCES pipelines (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS pipeline_transitions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    pipeline_id BIGINT UNSIGNED,
+    from_stage VARCHAR(64),
+    to_stage VARCHAR(64),
+    INDEX idx_pipeline_transitions_pipeline_id (pipeline_id),
+    CONSTRAINT fk_pipelines_transitions FOREIGN KEY (pipeline_id) REFERENCES pipelines (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alert_matches;
+DROP TABLE IF EXISTS saved_searches;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(255),
+    name VARCHAR(255),
+    query VARCHAR(255),
+    company VARCHAR(255),
+    city VARCHAR(100),
+    state VARCHAR(100),
+    frequency VARCHAR(16),
+    last_notified_at BIGINT,
+    created_at BIGINT,
+    INDEX idx_saved_searches_email (email),
+    INDEX idx_saved_searches_frequency (frequency)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS alert_matches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    saved_search_id BIGINT UNSIGNED,
+    job_id BIGINT UNSIGNED,
+    matched_at BIGINT,
+    delivered_at BIGINT,
+    UNIQUE INDEX idx_alert_match (saved_search_id, job_id),
+    INDEX idx_alert_matches_delivered_at (delivered_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    url VARCHAR(2048),
+    secret VARCHAR(128),
+    events VARCHAR(255),
+    active BOOLEAN,
+    created_at BIGINT,
+    INDEX idx_webhook_subscriptions_active (active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT UNSIGNED,
+    event_id VARCHAR(64),
+    event_type VARCHAR(64),
+    payload TEXT,
+    status VARCHAR(16),
+    attempts BIGINT,
+    next_attempt_at BIGINT,
+    last_error TEXT,
+    created_at BIGINT,
+    updated_at BIGINT,
+    UNIQUE INDEX idx_webhook_delivery_event (subscription_id, event_id),
+    INDEX idx_webhook_delivery_due (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    delivery_id BIGINT UNSIGNED,
+    attempt BIGINT,
+    status_code BIGINT,
+    error TEXT,
+    duration_ms BIGINT,
+    created_at BIGINT,
+    INDEX idx_webhook_delivery_attempts_delivery_id (delivery_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
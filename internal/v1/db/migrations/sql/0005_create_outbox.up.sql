@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_id VARCHAR(64),
+    aggregate_type VARCHAR(64),
+    aggregate_id BIGINT UNSIGNED,
+    event_type VARCHAR(64),
+    payload TEXT,
+    attempts BIGINT,
+    last_error TEXT,
+    created_at BIGINT,
+    processed_at BIGINT,
+    UNIQUE INDEX idx_outbox_event_id (event_id),
+    INDEX idx_outbox_processed_at (processed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
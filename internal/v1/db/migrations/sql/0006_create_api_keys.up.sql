@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    company VARCHAR(255),
+    name VARCHAR(255),
+    prefix VARCHAR(16),
+    hash VARCHAR(64),
+    created_by VARCHAR(255),
+    last_used_at BIGINT,
+    revoked_at BIGINT,
+    created_at BIGINT,
+    INDEX idx_api_keys_company (company),
+    UNIQUE INDEX idx_api_keys_prefix (prefix)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
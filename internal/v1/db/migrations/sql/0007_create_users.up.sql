@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS users (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(255),
+    password_hash VARCHAR(60),
+    name VARCHAR(255),
+    role VARCHAR(32),
+    created_at BIGINT,
+    updated_at BIGINT,
+    UNIQUE INDEX idx_users_email (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- The reconciled columns and indexes are part of the schema created by
+-- 0001, so there is nothing to revert.
+DO 0;
@@ -0,0 +1,83 @@
+-- Databases created before migrations existed already have a jobs table, so
+-- 0001 skipped it. AutoMigrate created it with LONGTEXT columns and none of
+-- the search indexes; bring it in line with 0001.
+ALTER TABLE jobs
+    MODIFY title VARCHAR(255) NOT NULL,
+    MODIFY company VARCHAR(255) NOT NULL,
+    MODIFY city VARCHAR(100) NOT NULL,
+    MODIFY state VARCHAR(100) NOT NULL,
+    MODIFY status BOOLEAN DEFAULT TRUE,
+    MODIFY created_at BIGINT NOT NULL;
+
+-- A LONGTEXT description may hold more than TEXT does, so it keeps its
+-- type and only becomes NOT NULL like in 0001.
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND column_name = 'description' AND data_type = 'longtext') = 1,
+    'ALTER TABLE jobs MODIFY description LONGTEXT NOT NULL', 'ALTER TABLE jobs MODIFY description TEXT NOT NULL');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Tables created by the old init.sql predate job ownership.
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND column_name = 'owner_id') = 0,
+    'ALTER TABLE jobs ADD COLUMN owner_id VARCHAR(255)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_title') = 0,
+    'CREATE INDEX idx_title ON jobs (title)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_company') = 0,
+    'CREATE INDEX idx_company ON jobs (company)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_city') = 0,
+    'CREATE INDEX idx_city ON jobs (city)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_state') = 0,
+    'CREATE INDEX idx_state ON jobs (state)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_status') = 0,
+    'CREATE INDEX idx_status ON jobs (status)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_created_at') = 0,
+    'CREATE INDEX idx_created_at ON jobs (created_at)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_jobs_owner_id') = 0,
+    'CREATE INDEX idx_jobs_owner_id ON jobs (owner_id)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND index_name = 'idx_search') = 0,
+    'CREATE FULLTEXT INDEX idx_search ON jobs (title, description, company, city, state)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS job_translations;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND column_name = 'lang') = 1,
+    'ALTER TABLE jobs DROP COLUMN lang', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
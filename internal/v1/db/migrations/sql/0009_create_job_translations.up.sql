@@ -0,0 +1,22 @@
+-- Jobs posted before translations existed are in jobs.default_language;
+-- an empty lang stands for it.
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'jobs' AND column_name = 'lang') = 0,
+    'ALTER TABLE jobs ADD COLUMN lang VARCHAR(35) NOT NULL DEFAULT ''''', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+CREATE TABLE IF NOT EXISTS job_translations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    job_id BIGINT UNSIGNED NOT NULL,
+    lang VARCHAR(35) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    UNIQUE INDEX idx_job_translations_job_lang (job_id, lang),
+    INDEX idx_job_translations_title (title),
+    FULLTEXT idx_job_translations_search (title, description),
+    CONSTRAINT fk_jobs_translations FOREIGN KEY (job_id) REFERENCES jobs (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'saved_searches' AND column_name = 'owner_id') = 1,
+    'ALTER TABLE saved_searches DROP INDEX idx_saved_searches_owner_id, DROP COLUMN owner_id', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,8 @@
+-- Searches saved before owners were recorded have an empty owner_id and
+-- are visible to admins only.
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'saved_searches' AND column_name = 'owner_id') = 0,
+    'ALTER TABLE saved_searches ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '''' AFTER id, ADD INDEX idx_saved_searches_owner_id (owner_id)', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
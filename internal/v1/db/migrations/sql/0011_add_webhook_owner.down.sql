@@ -0,0 +1,6 @@
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'webhook_subscriptions' AND column_name = 'owner_id') = 1,
+    'ALTER TABLE webhook_subscriptions DROP INDEX idx_webhook_subscriptions_owner_id, DROP COLUMN owner_id', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
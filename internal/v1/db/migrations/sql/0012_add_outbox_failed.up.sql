@@ -0,0 +1,6 @@
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'outbox' AND column_name = 'failed') = 0,
+    'ALTER TABLE outbox ADD COLUMN failed BOOLEAN NOT NULL DEFAULT FALSE AFTER last_error', 'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
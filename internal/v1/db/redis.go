@@ -3,8 +3,11 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/tracing"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -12,12 +15,28 @@ type RedisClient struct {
 	client *redis.Client
 }
 
-func NewRedisClient(addr, password string, db int) *RedisClient {
+type RedisOptions struct {
+	Addr        string
+	Password    string
+	DB          int
+	PoolSize    int
+	DialTimeout time.Duration
+	ReadTimeout time.Duration
+}
+
+func NewRedisClient(opts RedisOptions) *RedisClient {
 	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
+		Addr:        opts.Addr,
+		Password:    opts.Password,
+		DB:          opts.DB,
+		PoolSize:    opts.PoolSize,
+		DialTimeout: opts.DialTimeout,
+		ReadTimeout: opts.ReadTimeout,
+		// Writes share the read deadline, as in go-redis' defaults.
+		WriteTimeout: opts.ReadTimeout,
 	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
 
 	return &RedisClient{client: client}
 }
@@ -38,6 +57,92 @@ func (r *RedisClient) Get(ctx context.Context, key string, dest interface{}) err
 	return json.Unmarshal([]byte(val), dest)
 }
 
+// GetWithTTL is Get that also returns the remaining time to live of key,
+// in the same round trip. The TTL is negative for a key without expiry.
+func (r *RedisClient) GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ttl.Val(), json.Unmarshal([]byte(get.Val()), dest)
+}
+
+// TryLock sets key to token unless the key exists, reporting whether it
+// did. The lock expires after ttl even if its holder never unlocks it.
+func (r *RedisClient) TryLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, token, ttl).Result()
+}
+
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Unlock deletes key only while it still holds token, so that a lock that
+// expired and was taken by someone else stays in place.
+func (r *RedisClient) Unlock(ctx context.Context, key, token string) error {
+	return unlockScript.Run(ctx, r.client, []string{key}, token).Err()
+}
+
+// Generations returns the values of the generation counters keys, with ""
+// for a counter that does not exist.
+func (r *RedisClient) Generations(ctx context.Context, keys ...string) ([]string, error) {
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	gens := make([]string, len(vals))
+	for i, v := range vals {
+		gens[i], _ = v.(string)
+	}
+	return gens, nil
+}
+
+// BumpGeneration increments the generation counter key, which expires
+// after ttl.
+func (r *RedisClient) BumpGeneration(ctx context.Context, key string, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, key)
+		pipe.PExpire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+var setIfGenerationsScript = redis.NewScript(`
+for i = 2, #KEYS do
+	if (redis.call("GET", KEYS[i]) or "") ~= ARGV[i + 1] then
+		return 0
+	end
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+// SetIfGenerations is Set that only writes while the generation counters
+// genKeys still hold gens, as returned by Generations. It reports whether
+// it wrote the value.
+func (r *RedisClient) SetIfGenerations(ctx context.Context, key string, value interface{}, expiration time.Duration, genKeys, gens []string) (bool, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	args := []interface{}{jsonValue, expiration.Milliseconds()}
+	for _, gen := range gens {
+		args = append(args, gen)
+	}
+	n, err := setIfGenerationsScript.Run(ctx, r.client, append([]string{key}, genKeys...), args...).Int()
+	return n == 1, err
+}
+
 func (r *RedisClient) Del(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
 }
@@ -53,6 +158,22 @@ func (r *RedisClient) DelPattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
+// CountKeys counts the keys matching pattern with SCAN, so that it does not
+// block the server the way KEYS does.
+func (r *RedisClient) CountKeys(ctx context.Context, pattern string) (int64, error) {
+	var n int64
+	iter := r.client.Scan(ctx, 0, pattern, 1000).Iterator()
+	for iter.Next(ctx) {
+		n++
+	}
+	return n, iter.Err()
+}
+
+// Info returns the INFO output for section, e.g. "stats" or "memory".
+func (r *RedisClient) Info(ctx context.Context, section string) (string, error) {
+	return r.client.Info(ctx, section).Result()
+}
+
 func (r *RedisClient) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
@@ -60,3 +181,29 @@ func (r *RedisClient) Ping(ctx context.Context) error {
 func (r *RedisClient) Close() error {
 	return r.client.Close()
 }
+
+// XAdd appends an entry to a stream, trimming it to roughly maxLen entries
+// when maxLen is positive.
+func (r *RedisClient) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) (string, error) {
+	return r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Result()
+}
+
+// Eval runs a Lua script, using EVALSHA when the script is already loaded.
+func (r *RedisClient) Eval(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) *redis.Cmd {
+	return script.Run(ctx, r.client, keys, args...)
+}
+
+func (r *RedisClient) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := r.client.Exists(ctx, key).Result()
+	return n > 0, err
+}
+
+// IsNil reports whether err means the key does not exist.
+func IsNil(err error) bool {
+	return errors.Is(err, redis.Nil)
+}
@@ -2,117 +2,187 @@ package jobs
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
 	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"golang.org/x/sync/singleflight"
 )
 
+// Key families, used as metric labels.
+const (
+	familyJob        = "job"
+	familyJobsList   = "jobs_list"
+	familyJobsSearch = "jobs_search"
+)
+
+// generationTTL is how long an invalidation is remembered. It only has to
+// outlast the loads that were running at the time.
+const generationTTL = time.Hour
+
+// CacheTTLs sets how long each key family is cached.
+type CacheTTLs struct {
+	Job    time.Duration
+	List   time.Duration
+	Search time.Duration
+}
+
+// JobCache loads entries through fetch, which merges concurrent misses;
+// see StampedeOptions.
 type JobCache struct {
-	redis *db.RedisClient
+	redis    *db.RedisClient
+	ttls     atomic.Pointer[CacheTTLs]
+	stampede atomic.Pointer[StampedeOptions]
+	flights  singleflight.Group
+	// loadTimes holds how long the last load of each family took, in
+	// nanoseconds.
+	loadTimes map[string]*atomic.Int64
 }
 
-func NewJobCache(redis *db.RedisClient) *JobCache {
-	return &JobCache{redis: redis}
+func NewJobCache(redis *db.RedisClient, ttls CacheTTLs, stampede StampedeOptions) *JobCache {
+	c := &JobCache{
+		redis: redis,
+		loadTimes: map[string]*atomic.Int64{
+			familyJob:        new(atomic.Int64),
+			familyJobsList:   new(atomic.Int64),
+			familyJobsSearch: new(atomic.Int64),
+		},
+	}
+	c.SetTTLs(ttls)
+	c.SetStampede(stampede)
+	return c
+}
+
+// SetTTLs applies to entries written from now on; cached entries keep
+// their expiry.
+func (c *JobCache) SetTTLs(ttls CacheTTLs) {
+	c.ttls.Store(&ttls)
 }
 
 func (c *JobCache) getJobKey(id uint) string {
 	return fmt.Sprintf("job:%d", id)
 }
 
-func (c *JobCache) getJobsListKey(page, limit int) string {
-	return fmt.Sprintf("jobs:list:%d:%d", page, limit)
+// listScope keeps pages that include inactive jobs apart from public ones.
+func listScope(includeInactive bool) string {
+	if includeInactive {
+		return "all"
+	}
+	return "active"
 }
 
-func (c *JobCache) getJobsSearchKey(query string, page, limit int) string {
-	return fmt.Sprintf("jobs:search:%s:%d:%d", query, page, limit)
+func (c *JobCache) getJobsListKey(includeInactive bool, page, limit int) string {
+	return fmt.Sprintf("jobs:list:%s:%d:%d", listScope(includeInactive), page, limit)
+}
+
+func (c *JobCache) getJobsSearchKey(includeInactive bool, query string, page, limit int) string {
+	return fmt.Sprintf("jobs:search:%s:%s:%d:%d", listScope(includeInactive), query, page, limit)
 }
 
 func (c *JobCache) SetJob(ctx context.Context, job *Job) error {
 	key := c.getJobKey(job.ID)
-	return c.redis.Set(ctx, key, job, 30*time.Minute)
+	return c.redis.Set(ctx, key, job, c.ttls.Load().Job)
 }
 
-func (c *JobCache) GetJob(ctx context.Context, id uint) (*Job, error) {
-	key := c.getJobKey(id)
-	var job Job
-	err := c.redis.Get(ctx, key, &job)
-	if err != nil {
-		return nil, err
-	}
-	return &job, nil
+// fetchJob returns the cached job with the given id, loading it on a miss.
+func (c *JobCache) fetchJob(ctx context.Context, id uint, load func(context.Context) (Job, error)) (Job, bool, error) {
+	return fetch(ctx, c, familyJob, c.getJobKey(id), c.ttls.Load().Job, load)
 }
 
-func (c *JobCache) SetJobsList(ctx context.Context, page, limit int, jobs []Job, total int64) error {
-	key := c.getJobsListKey(page, limit)
-	data := map[string]interface{}{
-		"jobs":  jobs,
-		"total": total,
-	}
-	return c.redis.Set(ctx, key, data, 15*time.Minute)
+// jobPage is a cached page of a list or search. The JSON matches the
+// entries of earlier versions, so they stay readable.
+type jobPage struct {
+	Jobs  []Job `json:"jobs"`
+	Total int64 `json:"total"`
 }
 
-func (c *JobCache) GetJobsList(ctx context.Context, page, limit int) ([]Job, int64, error) {
-	key := c.getJobsListKey(page, limit)
-	var data map[string]interface{}
-	err := c.redis.Get(ctx, key, &data)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	jobsData := data["jobs"].([]interface{})
-	jobs := make([]Job, len(jobsData))
-	for i, jobData := range jobsData {
-		jobBytes, _ := json.Marshal(jobData)
-		json.Unmarshal(jobBytes, &jobs[i])
-	}
-
-	total := int64(data["total"].(float64))
-	return jobs, total, nil
+func (c *JobCache) fetchJobsList(ctx context.Context, includeInactive bool, page, limit int, load func(context.Context) (jobPage, error)) (jobPage, bool, error) {
+	key := c.getJobsListKey(includeInactive, page, limit)
+	return fetch(ctx, c, familyJobsList, key, c.ttls.Load().List, load)
 }
 
-func (c *JobCache) SetJobsSearch(ctx context.Context, query string, page, limit int, jobs []Job, total int64) error {
-	key := c.getJobsSearchKey(query, page, limit)
-	data := map[string]interface{}{
-		"jobs":  jobs,
-		"total": total,
-	}
-	return c.redis.Set(ctx, key, data, 10*time.Minute)
+func (c *JobCache) fetchJobsSearch(ctx context.Context, includeInactive bool, query string, page, limit int, load func(context.Context) (jobPage, error)) (jobPage, bool, error) {
+	key := c.getJobsSearchKey(includeInactive, query, page, limit)
+	return fetch(ctx, c, familyJobsSearch, key, c.ttls.Load().Search, load)
 }
 
-func (c *JobCache) GetJobsSearch(ctx context.Context, query string, page, limit int) ([]Job, int64, error) {
-	key := c.getJobsSearchKey(query, page, limit)
-	var data map[string]interface{}
-	err := c.redis.Get(ctx, key, &data)
-	if err != nil {
-		return nil, 0, err
+// generationKeys are the counters an invalidation of key bumps: one per
+// job, one per list family, and one for InvalidateAll. A load that sees
+// any of them change does not cache its result; see fill.
+func generationKeys(family, key string) []string {
+	scope := family
+	if family == familyJob {
+		scope = key
 	}
+	return []string{"gen:" + scope, "gen:all"}
+}
 
-	jobsData := data["jobs"].([]interface{})
-	jobs := make([]Job, len(jobsData))
-	for i, jobData := range jobsData {
-		jobBytes, _ := json.Marshal(jobData)
-		json.Unmarshal(jobBytes, &jobs[i])
+// invalidate bumps the generation of scope before deleting the entries, so
+// that loads already running cannot write them back.
+func (c *JobCache) invalidate(ctx context.Context, scope string, del func() error) error {
+	genErr := c.redis.BumpGeneration(ctx, "gen:"+scope, generationTTL)
+	if err := del(); err != nil {
+		return err
 	}
-
-	total := int64(data["total"].(float64))
-	return jobs, total, nil
+	return genErr
 }
 
 func (c *JobCache) InvalidateJob(ctx context.Context, id uint) error {
+	metrics.CacheInvalidations.WithLabelValues(familyJob).Inc()
 	key := c.getJobKey(id)
-	return c.redis.Del(ctx, key)
+	return c.invalidate(ctx, key, func() error { return c.redis.Del(ctx, key) })
 }
 
 func (c *JobCache) InvalidateJobsList(ctx context.Context) error {
-	return c.redis.DelPattern(ctx, "jobs:list:*")
+	metrics.CacheInvalidations.WithLabelValues(familyJobsList).Inc()
+	return c.invalidate(ctx, familyJobsList, func() error { return c.redis.DelPattern(ctx, "jobs:list:*") })
 }
 
 func (c *JobCache) InvalidateJobsSearch(ctx context.Context) error {
-	return c.redis.DelPattern(ctx, "jobs:search:*")
+	metrics.CacheInvalidations.WithLabelValues(familyJobsSearch).Inc()
+	return c.invalidate(ctx, familyJobsSearch, func() error { return c.redis.DelPattern(ctx, "jobs:search:*") })
 }
 
+// InvalidateAll drops every cached job, list page and search page.
 func (c *JobCache) InvalidateAll(ctx context.Context) error {
-	return c.redis.DelPattern(ctx, "job:*")
+	metrics.CacheInvalidations.WithLabelValues(familyJob).Inc()
+	err := c.invalidate(ctx, "all", func() error { return c.redis.DelPattern(ctx, "job:*") })
+	if err != nil {
+		return err
+	}
+	if err := c.InvalidateJobsList(ctx); err != nil {
+		return err
+	}
+	return c.InvalidateJobsSearch(ctx)
+}
+
+// Stats counts the cached keys of each family.
+func (c *JobCache) Stats(ctx context.Context) (map[string]int64, error) {
+	patterns := map[string]string{
+		familyJob:        "job:*",
+		familyJobsList:   "jobs:list:*",
+		familyJobsSearch: "jobs:search:*",
+	}
+	stats := make(map[string]int64, len(patterns))
+	for family, pattern := range patterns {
+		n, err := c.redis.CountKeys(ctx, pattern)
+		if err != nil {
+			return nil, err
+		}
+		stats[family] = n
+	}
+	return stats, nil
+}
+
+func recordLookup(family string, err error) {
+	result := "hit"
+	switch {
+	case db.IsNil(err):
+		result = "miss"
+	case err != nil:
+		result = "error"
+	}
+	metrics.CacheRequests.WithLabelValues(family, result).Inc()
 }
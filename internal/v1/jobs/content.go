@@ -0,0 +1,177 @@
+package jobs
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"golang.org/x/text/language"
+)
+
+// ContentRules cleans up the text of job postings and limits its length,
+// in characters. DefaultLanguage is the language of jobs posted without
+// one.
+type ContentRules struct {
+	TitleMaxLength       int
+	DescriptionMaxLength int
+	CompanyMaxLength     int
+	CityMaxLength        int
+	DefaultLanguage      string
+}
+
+// Create cleans up req in place and reports every invalid field at once.
+func (r ContentRules) Create(req *CreateJobRequest) error {
+	req.Title = cleanLine(req.Title)
+	req.Description = cleanDescription(req.Description)
+	req.Company = cleanLine(req.Company)
+	req.City = cleanLine(req.City)
+	req.State = strings.ToUpper(cleanLine(req.State))
+	req.Lang = cleanLine(req.Lang)
+	if req.Lang == "" {
+		req.Lang = r.DefaultLanguage
+	}
+
+	var v contentValidator
+	v.text("title", req.Title, r.TitleMaxLength)
+	v.text("description", req.Description, r.DescriptionMaxLength)
+	v.text("company", req.Company, r.CompanyMaxLength)
+	v.text("city", req.City, r.CityMaxLength)
+	v.region("state", req.State)
+	v.lang("lang", &req.Lang)
+	return v.err()
+}
+
+// Update does the same for the fields present in req; a present field must
+// not be empty either.
+func (r ContentRules) Update(req *UpdateJobRequest) error {
+	var v contentValidator
+	if req.Title != nil {
+		*req.Title = cleanLine(*req.Title)
+		v.text("title", *req.Title, r.TitleMaxLength)
+	}
+	if req.Description != nil {
+		*req.Description = cleanDescription(*req.Description)
+		v.text("description", *req.Description, r.DescriptionMaxLength)
+	}
+	if req.Company != nil {
+		*req.Company = cleanLine(*req.Company)
+		v.text("company", *req.Company, r.CompanyMaxLength)
+	}
+	if req.City != nil {
+		*req.City = cleanLine(*req.City)
+		v.text("city", *req.City, r.CityMaxLength)
+	}
+	if req.State != nil {
+		*req.State = strings.ToUpper(cleanLine(*req.State))
+		v.region("state", *req.State)
+	}
+	if req.Lang != nil {
+		*req.Lang = cleanLine(*req.Lang)
+		v.lang("lang", req.Lang)
+	}
+	return v.err()
+}
+
+// Translation cleans up req like the title and description of a job.
+func (r ContentRules) Translation(req *TranslationRequest) error {
+	req.Title = cleanLine(req.Title)
+	req.Description = cleanDescription(req.Description)
+
+	var v contentValidator
+	v.text("title", req.Title, r.TitleMaxLength)
+	v.text("description", req.Description, r.DescriptionMaxLength)
+	v.lang("lang", &req.Lang)
+	return v.err()
+}
+
+// cleanLine drops control characters and collapses every run of
+// whitespace, including line breaks, into a single space.
+func cleanLine(s string) string {
+	return strings.Join(strings.Fields(dropControl(s)), " ")
+}
+
+// cleanDescription unifies the line endings of the Markdown source, strips
+// trailing whitespace and keeps at most one blank line between paragraphs.
+// Hard line breaks therefore need a trailing backslash rather than spaces.
+// HTML is sanitized when the description is rendered; see Job.Render.
+func cleanDescription(s string) string {
+	s = strings.ReplaceAll(dropControl(s), "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" && blank {
+			continue
+		}
+		blank = line == ""
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+func dropControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+type contentValidator struct {
+	fields []apperr.FieldError
+}
+
+func (v *contentValidator) text(field, value string, maxLength int) {
+	switch {
+	case value == "":
+		v.fields = append(v.fields, apperr.FieldError{Field: field, Rule: "required", Message: "is required"})
+	case utf8.RuneCountInString(value) > maxLength:
+		v.fields = append(v.fields, apperr.FieldError{
+			Field:   field,
+			Rule:    "max",
+			Message: "must be at most " + strconv.Itoa(maxLength) + " characters long",
+		})
+	}
+}
+
+func (v *contentValidator) region(field, value string) {
+	switch {
+	case value == "":
+		v.fields = append(v.fields, apperr.FieldError{Field: field, Rule: "required", Message: "is required"})
+	case !ValidRegion(value):
+		v.fields = append(v.fields, apperr.FieldError{
+			Field:   field,
+			Rule:    "region",
+			Message: "must be an ISO 3166-1 country code or a US state or Canadian province code",
+		})
+	}
+}
+
+// lang replaces a valid BCP 47 tag with its canonical form, so that "EN_us"
+// and "en-US" are stored alike.
+func (v *contentValidator) lang(field string, value *string) {
+	tag, err := language.Parse(*value)
+	switch {
+	case *value == "":
+		v.fields = append(v.fields, apperr.FieldError{Field: field, Rule: "required", Message: "is required"})
+	case err != nil || tag == language.Und:
+		v.fields = append(v.fields, apperr.FieldError{
+			Field:   field,
+			Rule:    "bcp47",
+			Message: "must be a BCP 47 language tag such as en or tr-TR",
+		})
+	default:
+		*value = tag.String()
+	}
+}
+
+func (v *contentValidator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return apperr.InvalidFields(v.fields...)
+}
@@ -1,11 +1,15 @@
 package jobs
 
+// CreateJobRequest is checked by ContentRules rather than binding tags, so
+// that every invalid field is reported at once.
 type CreateJobRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"required"`
-	Company     string `json:"company" binding:"required"`
-	City        string `json:"city" binding:"required"`
-	State       string `json:"state" binding:"required"`
+	Title       string `json:"title"`
+	Description string `json:"description"` // Markdown
+	Company     string `json:"company"`
+	City        string `json:"city"`
+	State       string `json:"state" example:"TR"`
+	// Lang defaults to the configured default language.
+	Lang string `json:"lang" example:"tr"`
 }
 
 type UpdateJobRequest struct {
@@ -15,15 +19,44 @@ type UpdateJobRequest struct {
 	City        *string `json:"city"`
 	State       *string `json:"state"`
 	Status      *bool   `json:"status"`
+	Lang        *string `json:"lang"`
 }
 
-type JobResponse struct {
-	ID          uint   `json:"id"`
+// TranslationRequest takes Lang from the URL. Like CreateJobRequest, it is
+// checked by ContentRules.
+type TranslationRequest struct {
+	Lang        string `json:"-"`
 	Title       string `json:"title"`
-	Description string `json:"description"`
-	Company     string `json:"company"`
-	City        string `json:"city"`
-	State       string `json:"state"`
-	CreatedAt   int64  `json:"created_at"`
-	Status      bool   `json:"status"`
+	Description string `json:"description"` // Markdown
+}
+
+type TranslationResponse struct {
+	Lang                string `json:"lang"`
+	Title               string `json:"title"`
+	DescriptionMarkdown string `json:"description_markdown"`
+	DescriptionHTML     string `json:"description_html"`
+	Excerpt             string `json:"excerpt"`
+	CreatedAt           int64  `json:"created_at"`
+	UpdatedAt           int64  `json:"updated_at"`
+}
+
+// JobResponse carries the description as stored (Markdown), rendered to
+// sanitized HTML, and as a plain-text excerpt for list views. Description
+// repeats DescriptionMarkdown for clients of the original v1 response.
+// Title and description are in Lang, picked from Languages for the caller.
+type JobResponse struct {
+	ID                  uint     `json:"id"`
+	Title               string   `json:"title"`
+	Description         string   `json:"description"`
+	DescriptionMarkdown string   `json:"description_markdown"`
+	DescriptionHTML     string   `json:"description_html"`
+	Excerpt             string   `json:"excerpt"`
+	Company             string   `json:"company"`
+	City                string   `json:"city"`
+	State               string   `json:"state"`
+	CreatedAt           int64    `json:"created_at"`
+	Status              bool     `json:"status"`
+	OwnerID             string   `json:"owner_id"`
+	Lang                string   `json:"lang"`
+	Languages           []string `json:"languages"`
 }
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/outbox"
+	"gorm.io/gorm"
+)
+
+type EventType string
+
+const (
+	EventJobCreated EventType = "job.created"
+	EventJobUpdated EventType = "job.updated"
+	EventJobDeleted EventType = "job.deleted"
+	// EventJobStatusChanged is published in addition to EventJobUpdated when
+	// an update opens or closes a posting.
+	EventJobStatusChanged EventType = "job.status_changed"
+)
+
+var EventTypes = []EventType{EventJobCreated, EventJobUpdated, EventJobDeleted, EventJobStatusChanged}
+
+type JobEvent struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	Job        Job       `json:"job"`
+	OccurredAt int64     `json:"occurred_at"`
+}
+
+func NewJobEvent(eventType EventType, job Job) JobEvent {
+	return JobEvent{ID: newEventID(), Type: eventType, Job: job, OccurredAt: time.Now().Unix()}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// EventPublisher receives job lifecycle events after the change has been
+// committed. Events are relayed from the outbox and may be delivered more
+// than once, so publishers must be idempotent on JobEvent.ID. Returning an
+// error makes the relay retry the event later.
+type EventPublisher interface {
+	Publish(ctx context.Context, event JobEvent) error
+}
+
+// Publishers fans an event out to every publisher in the list.
+type Publishers []EventPublisher
+
+func (p Publishers) Publish(ctx context.Context, event JobEvent) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OutboxHandler decodes job events written to the outbox and hands them to
+// the publisher.
+func OutboxHandler(publisher EventPublisher) outbox.Handler {
+	return func(ctx context.Context, msg outbox.Message) error {
+		var event JobEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			return err
+		}
+		return publisher.Publish(ctx, event)
+	}
+}
+
+func enqueueEvent(tx *gorm.DB, eventType EventType, job Job) error {
+	event := NewJobEvent(eventType, job)
+	return outbox.Enqueue(tx, event.ID, "job", job.ID, string(event.Type), event)
+}
@@ -1,35 +1,64 @@
 package jobs
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/pagination"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
 )
 
 type JobHandler struct {
-	repo JobRepository
+	repo  JobRepository
+	pages pagination.Limits
+	rules ContentRules
 }
 
-func NewJobHandler(repo JobRepository) *JobHandler {
-	return &JobHandler{repo: repo}
+func NewJobHandler(repo JobRepository, pages pagination.Limits, rules ContentRules) *JobHandler {
+	// Languages are stored as canonical tags, so the default must be one too.
+	rules.DefaultLanguage = language.Make(rules.DefaultLanguage).String()
+	return &JobHandler{repo: repo, pages: pages, rules: rules}
 }
 
 // CreateJob godoc
 // @Summary      Create a new job
-// @Description  Add a new job posting
+// @Description  Add a new job posting. Text is trimmed and length-limited, the description is Markdown, state must be a country, US state or Canadian province code and lang a BCP 47 tag (the default language if omitted).
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        job  body  CreateJobRequest  true  "Job info"
 // @Success      201  {object}  JobResponse
-// @Failure      400  {object}  map[string]string
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
 // @Router       /jobs [post]
 func (h *JobHandler) CreateJob(c *gin.Context) {
 	var req CreateJobRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Bind(c, err)
+		return
+	}
+	if err := h.rules.Create(&req); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	// Callers post jobs for their own company only. Self-registered
+	// accounts carry no company, so they cannot name one at will.
+	principal := auth.GetPrincipal(c)
+	if principal != nil && !principal.HasRole("admin") && req.Company != principal.Company {
+		switch {
+		case principal.Kind == auth.KindAPIKey:
+			problem.Abort(c, problem.TypeForbidden, "API key cannot post jobs for another company")
+		case principal.Company == "":
+			problem.Abort(c, problem.TypeForbidden, "Account is not linked to a company")
+		default:
+			problem.Abort(c, problem.TypeForbidden, "Cannot post jobs for another company")
+		}
 		return
 	}
 	job := Job{
@@ -40,63 +69,46 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 		State:       req.State,
 		Status:      true,
 		CreatedAt:   time.Now().Unix(),
+		Lang:        req.Lang,
+	}
+	if principal != nil {
+		job.OwnerID = principal.ID
 	}
 	if err := h.repo.Create(c.Request.Context(), &job); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
-		return
-	}
-	c.JSON(http.StatusCreated, JobResponse{
-		ID:          job.ID,
-		Title:       job.Title,
-		Description: job.Description,
-		Company:     job.Company,
-		City:        job.City,
-		State:       job.State,
-		CreatedAt:   job.CreatedAt,
-		Status:      job.Status,
-	})
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, h.toJobResponse(&job, nil))
 }
 
 // ListJobs godoc
 // @Summary      List jobs
-// @Description  Get jobs with pagination
+// @Description  Get jobs with pagination, each in the language that best matches lang or Accept-Language
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        page   query     int false "Page number"
 // @Param        limit  query     int false "Page size"
+// @Param        lang   query     string false "Preferred language, before Accept-Language"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /jobs [get]
 func (h *JobHandler) ListJobs(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "10")
-	page, _ := strconv.Atoi(pageStr)
-	limit, _ := strconv.Atoi(limitStr)
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
+	want, ok := h.requestedLanguages(c)
+	if !ok {
+		return
 	}
+	page, limit := h.pages.Parse(c)
 	offset := (page - 1) * limit
-	jobs, total, err := h.repo.List(c.Request.Context(), offset, limit)
+	jobs, total, err := h.repo.List(c.Request.Context(), auth.Can(c, ActionReadInactive), offset, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+		problem.Error(c, err)
 		return
 	}
 	responses := make([]JobResponse, len(jobs))
-	for i, job := range jobs {
-		responses[i] = JobResponse{
-			ID:          job.ID,
-			Title:       job.Title,
-			Description: job.Description,
-			Company:     job.Company,
-			City:        job.City,
-			State:       job.State,
-			CreatedAt:   job.CreatedAt,
-			Status:      job.Status,
-		}
+	for i := range jobs {
+		responses[i] = h.toJobResponse(&jobs[i], want)
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"jobs":  responses,
@@ -112,18 +124,33 @@ func (h *JobHandler) ListJobs(c *gin.Context) {
 // @Tags         jobs
 // @Param        id   path      int  true  "Job ID"
 // @Success      204  {string}  string  ""
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /jobs/{id} [delete]
 func (h *JobHandler) DeleteJob(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job id"})
+		problem.Abort(c, problem.TypeBadRequest, "Invalid job id")
+		return
+	}
+	job, err := h.repo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			// Deleting a missing job is a no-op.
+			c.Status(http.StatusNoContent)
+			return
+		}
+		problem.Error(c, err)
+		return
+	}
+	if !canModify(c, job, ActionDeleteAny) {
+		auth.Forbid(c, ActionDeleteAny, "Not the owner of this job")
 		return
 	}
 	if err := h.repo.Delete(c.Request.Context(), uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job"})
+		problem.Error(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -131,50 +158,38 @@ func (h *JobHandler) DeleteJob(c *gin.Context) {
 
 // SearchJobs godoc
 // @Summary      Search jobs
-// @Description  Search jobs by query string in title, description, company, city, or state
+// @Description  Search jobs by query string in title, description, company, city, or state, and in the title and description of every translation
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        q      query     string true  "Search query"
 // @Param        page   query     int    false "Page number"
 // @Param        limit  query     int    false "Page size"
+// @Param        lang   query     string false "Preferred language, before Accept-Language"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /jobs/search [get]
 func (h *JobHandler) SearchJobs(c *gin.Context) {
 	q := c.Query("q")
 	if q == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing search query"})
+		problem.Abort(c, problem.TypeBadRequest, "Missing search query")
 		return
 	}
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "10")
-	page, _ := strconv.Atoi(pageStr)
-	limit, _ := strconv.Atoi(limitStr)
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
+	want, ok := h.requestedLanguages(c)
+	if !ok {
+		return
 	}
+	page, limit := h.pages.Parse(c)
 	offset := (page - 1) * limit
-	jobs, total, err := h.repo.Search(c.Request.Context(), q, offset, limit)
+	jobs, total, err := h.repo.Search(c.Request.Context(), auth.Can(c, ActionReadInactive), q, offset, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search jobs"})
+		problem.Error(c, err)
 		return
 	}
 	responses := make([]JobResponse, len(jobs))
-	for i, job := range jobs {
-		responses[i] = JobResponse{
-			ID:          job.ID,
-			Title:       job.Title,
-			Description: job.Description,
-			Company:     job.Company,
-			City:        job.City,
-			State:       job.State,
-			CreatedAt:   job.CreatedAt,
-			Status:      job.Status,
-		}
+	for i := range jobs {
+		responses[i] = h.toJobResponse(&jobs[i], want)
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"jobs":  responses,
@@ -186,78 +201,90 @@ func (h *JobHandler) SearchJobs(c *gin.Context) {
 
 // GetJobByID godoc
 // @Summary      Get a job by ID
-// @Description  Get a specific job by its ID
+// @Description  Get a specific job by its ID, in the language that best matches lang or Accept-Language; Content-Language names it
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Job ID"
+// @Param        lang query     string false "Preferred language, before Accept-Language"
 // @Success      200  {object}  JobResponse
-// @Failure      400  {object}  map[string]string
-// @Failure      404  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /jobs/{id} [get]
 func (h *JobHandler) GetJobByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job id"})
+		problem.Abort(c, problem.TypeBadRequest, "Invalid job id")
+		return
+	}
+	want, ok := h.requestedLanguages(c)
+	if !ok {
 		return
 	}
 	job, err := h.repo.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		if err.Error() == "record not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
-		return
-	}
-	c.JSON(http.StatusOK, JobResponse{
-		ID:          job.ID,
-		Title:       job.Title,
-		Description: job.Description,
-		Company:     job.Company,
-		City:        job.City,
-		State:       job.State,
-		CreatedAt:   job.CreatedAt,
-		Status:      job.Status,
-	})
+		problem.Error(c, err)
+		return
+	}
+	if !job.Status && !canReadInactive(c, job) {
+		problem.Abort(c, problem.TypeNotFound, "Job not found")
+		return
+	}
+	response := h.toJobResponse(job, want)
+	c.Header("Content-Language", response.Lang)
+	c.JSON(http.StatusOK, response)
 }
 
 // UpdateJob godoc
 // @Summary      Update a job
-// @Description  Update a job by ID (partial update supported)
+// @Description  Update a job by ID (partial update supported). Title, description and lang are those of the original, not of a translation.
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Job ID"
 // @Param        job  body      UpdateJobRequest  true  "Job update info"
 // @Success      200  {object}  JobResponse
-// @Failure      400  {object}  map[string]string
-// @Failure      404  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /jobs/{id} [put]
 func (h *JobHandler) UpdateJob(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job id"})
+		problem.Abort(c, problem.TypeBadRequest, "Invalid job id")
 		return
 	}
 
 	var req UpdateJobRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Bind(c, err)
+		return
+	}
+	if err := h.rules.Update(&req); err != nil {
+		problem.Error(c, err)
 		return
 	}
 
-	_, err = h.repo.GetByID(c.Request.Context(), uint(id))
+	job, err := h.repo.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		if err.Error() == "record not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		problem.Error(c, err)
+		return
+	}
+	if !canModify(c, job, ActionUpdateAny) {
+		auth.Forbid(c, ActionUpdateAny, "Not the owner of this job")
+		return
+	}
+	principal := auth.GetPrincipal(c)
+	if req.Company != nil && principal != nil && principal.Kind == auth.KindAPIKey && *req.Company != principal.Company {
+		problem.Abort(c, problem.TypeForbidden, "API key cannot move jobs to another company")
+		return
+	}
+	if req.Lang != nil && job.translation(*req.Lang) != nil {
+		problem.Abort(c, problem.TypeConflict, "The job has a translation into "+*req.Lang+"; delete it first")
 		return
 	}
 
@@ -280,31 +307,290 @@ func (h *JobHandler) UpdateJob(c *gin.Context) {
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.Lang != nil {
+		updates["lang"] = *req.Lang
+	}
 
 	if len(updates) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		problem.Abort(c, problem.TypeBadRequest, "No fields to update")
 		return
 	}
 
 	if err := h.repo.Update(c.Request.Context(), uint(id), updates); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job"})
+		problem.Error(c, err)
 		return
 	}
 
 	updatedJob, err := h.repo.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get updated job"})
+		problem.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, JobResponse{
-		ID:          updatedJob.ID,
-		Title:       updatedJob.Title,
-		Description: updatedJob.Description,
-		Company:     updatedJob.Company,
-		City:        updatedJob.City,
-		State:       updatedJob.State,
-		CreatedAt:   updatedJob.CreatedAt,
-		Status:      updatedJob.Status,
+	c.JSON(http.StatusOK, h.toJobResponse(updatedJob, nil))
+}
+
+// ListTranslations godoc
+// @Summary      List translations of a job
+// @Description  Get the translations of a job, ordered by language. The job's own text is not included.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /jobs/{id}/translations [get]
+func (h *JobHandler) ListTranslations(c *gin.Context) {
+	job, ok := h.readableJob(c)
+	if !ok {
+		return
+	}
+	responses := make([]TranslationResponse, len(job.Translations))
+	for i := range job.Translations {
+		responses[i] = toTranslationResponse(&job.Translations[i])
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"lang":         job.language(h.rules.DefaultLanguage),
+		"translations": responses,
 	})
 }
+
+// GetTranslation godoc
+// @Summary      Get a translation of a job
+// @Description  Get the translation of a job into exactly the given language, without fallback
+// @Tags         jobs
+// @Produce      json
+// @Param        id    path      int     true  "Job ID"
+// @Param        lang  path      string  true  "BCP 47 language tag"
+// @Success      200  {object}  TranslationResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /jobs/{id}/translations/{lang} [get]
+func (h *JobHandler) GetTranslation(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	job, ok := h.readableJob(c)
+	if !ok {
+		return
+	}
+	translation := job.translation(lang)
+	if translation == nil {
+		problem.Abort(c, problem.TypeNotFound, "Translation not found")
+		return
+	}
+	c.Header("Content-Language", translation.Lang)
+	c.JSON(http.StatusOK, toTranslationResponse(translation))
+}
+
+// PutTranslation godoc
+// @Summary      Create or replace a translation of a job
+// @Description  Set the title and description of a job in another language. They are cleaned up and limited like those of the job.
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id           path  int                 true  "Job ID"
+// @Param        lang         path  string              true  "BCP 47 language tag"
+// @Param        translation  body  TranslationRequest  true  "Translated text"
+// @Success      200  {object}  TranslationResponse
+// @Success      201  {object}  TranslationResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /jobs/{id}/translations/{lang} [put]
+func (h *JobHandler) PutTranslation(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	var req TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	req.Lang = lang
+	if err := h.rules.Translation(&req); err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	job, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	if !canModify(c, job, ActionUpdateAny) {
+		auth.Forbid(c, ActionUpdateAny, "Not the owner of this job")
+		return
+	}
+	if req.Lang == job.language(h.rules.DefaultLanguage) {
+		problem.Abort(c, problem.TypeConflict, "The job is written in "+req.Lang+"; update the job instead")
+		return
+	}
+
+	translation := Translation{Lang: req.Lang, Title: req.Title, Description: req.Description}
+	created, err := h.repo.PutTranslation(c.Request.Context(), id, &translation)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, toTranslationResponse(&translation))
+}
+
+// DeleteTranslation godoc
+// @Summary      Delete a translation of a job
+// @Description  Delete the translation of a job into the given language
+// @Tags         jobs
+// @Param        id    path  int     true  "Job ID"
+// @Param        lang  path  string  true  "BCP 47 language tag"
+// @Success      204  {string}  string  ""
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /jobs/{id}/translations/{lang} [delete]
+func (h *JobHandler) DeleteTranslation(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	job, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	if !canModify(c, job, ActionUpdateAny) {
+		auth.Forbid(c, ActionUpdateAny, "Not the owner of this job")
+		return
+	}
+	if err := h.repo.DeleteTranslation(c.Request.Context(), id, lang); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// readableJob loads the job named by the id parameter, hiding inactive jobs
+// from callers who may not see them.
+func (h *JobHandler) readableJob(c *gin.Context) (*Job, bool) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return nil, false
+	}
+	job, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		problem.Error(c, err)
+		return nil, false
+	}
+	if !job.Status && !canReadInactive(c, job) {
+		problem.Abort(c, problem.TypeNotFound, "Job not found")
+		return nil, false
+	}
+	return job, true
+}
+
+// requestedLanguages also marks the response as varying by
+// Accept-Language, since it picks the language of every job.
+func (h *JobHandler) requestedLanguages(c *gin.Context) ([]language.Tag, bool) {
+	c.Header("Vary", "Accept-Language")
+	want, err := requestedLanguages(c)
+	if err != nil {
+		problem.Abort(c, problem.TypeBadRequest, "Invalid lang parameter, expected a BCP 47 language tag")
+		return nil, false
+	}
+	return want, true
+}
+
+// toJobResponse renders the description if the job came from an entry
+// cached before it was rendered, and picks the translation that best
+// matches want.
+func (h *JobHandler) toJobResponse(job *Job, want []language.Tag) JobResponse {
+	job.Render()
+	text := job.Localize(want, h.rules.DefaultLanguage)
+	return JobResponse{
+		ID:                  job.ID,
+		Title:               text.Title,
+		Description:         text.Description,
+		DescriptionMarkdown: text.Description,
+		DescriptionHTML:     text.DescriptionHTML,
+		Excerpt:             text.Excerpt,
+		Company:             job.Company,
+		City:                job.City,
+		State:               job.State,
+		CreatedAt:           job.CreatedAt,
+		Status:              job.Status,
+		OwnerID:             job.OwnerID,
+		Lang:                text.Lang,
+		Languages:           job.Languages(h.rules.DefaultLanguage),
+	}
+}
+
+func toTranslationResponse(t *Translation) TranslationResponse {
+	render(t.Description, &t.DescriptionHTML, &t.Excerpt)
+	return TranslationResponse{
+		Lang:                t.Lang,
+		Title:               t.Title,
+		DescriptionMarkdown: t.Description,
+		DescriptionHTML:     t.DescriptionHTML,
+		Excerpt:             t.Excerpt,
+		CreatedAt:           t.CreatedAt,
+		UpdatedAt:           t.UpdatedAt,
+	}
+}
+
+func parseJobID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		problem.Abort(c, problem.TypeBadRequest, "Invalid job id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseLang returns the lang parameter as a canonical tag, the form
+// translations are stored in.
+func parseLang(c *gin.Context) (string, bool) {
+	tag, err := language.Parse(c.Param("lang"))
+	if err != nil || tag == language.Und {
+		problem.Abort(c, problem.TypeBadRequest, "Invalid language, expected a BCP 47 language tag")
+		return "", false
+	}
+	return tag.String(), true
+}
+
+// canModify reports whether the caller may update or delete the job: its
+// owner, a member of the owning company, or a role granted anyAction. With
+// authentication disabled there is no principal and every caller may.
+func canModify(c *gin.Context, job *Job, anyAction string) bool {
+	principal := auth.GetPrincipal(c)
+	if principal == nil {
+		return true
+	}
+	return auth.Can(c, anyAction) || principal.Owns(job.OwnerID)
+}
+
+// canReadInactive lets owners see their own closed jobs; everyone else needs
+// the jobs:read:inactive permission.
+func canReadInactive(c *gin.Context, job *Job) bool {
+	if principal := auth.GetPrincipal(c); principal != nil && principal.Owns(job.OwnerID) {
+		return true
+	}
+	return auth.Can(c, ActionReadInactive)
+}
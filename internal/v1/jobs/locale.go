@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// requestedLanguages lists the languages the caller asked for, best first:
+// the lang query parameter, then Accept-Language. A malformed
+// Accept-Language header is ignored, as browsers send it unasked; a
+// malformed lang parameter is an error.
+func requestedLanguages(c *gin.Context) ([]language.Tag, error) {
+	var want []language.Tag
+	if lang := c.Query("lang"); lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, err
+		}
+		want = append(want, tag)
+	}
+	accepted, _, _ := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	return append(want, accepted...), nil
+}
+
+// Localize returns the title and description that best match want, as a
+// Translation. Close matches count, so "en-GB" picks an "en" translation
+// and "pt" a "pt-BR" one; when nothing matches, the job's own text is
+// returned. defaultLang is the language of jobs without Lang.
+func (j *Job) Localize(want []language.Tag, defaultLang string) Translation {
+	original := Translation{
+		Lang:            j.language(defaultLang),
+		Title:           j.Title,
+		Description:     j.Description,
+		DescriptionHTML: j.DescriptionHTML,
+		Excerpt:         j.Excerpt,
+	}
+	if len(j.Translations) == 0 || len(want) == 0 {
+		return original
+	}
+	// The matcher falls back to the first supported language.
+	supported := make([]language.Tag, 0, len(j.Translations)+1)
+	supported = append(supported, language.Make(original.Lang))
+	for _, t := range j.Translations {
+		supported = append(supported, language.Make(t.Lang))
+	}
+	_, i, confidence := language.NewMatcher(supported).Match(want...)
+	if i == 0 || confidence == language.No {
+		return original
+	}
+	return j.Translations[i-1]
+}
+
+// Languages lists the language of the job followed by those of its
+// translations.
+func (j *Job) Languages(defaultLang string) []string {
+	langs := make([]string, 0, len(j.Translations)+1)
+	langs = append(langs, j.language(defaultLang))
+	for _, t := range j.Translations {
+		langs = append(langs, t.Lang)
+	}
+	return langs
+}
+
+func (j *Job) language(defaultLang string) string {
+	if j.Lang == "" {
+		return defaultLang
+	}
+	return j.Lang
+}
+
+// translation returns the translation into lang, or nil.
+func (j *Job) translation(lang string) *Translation {
+	for i := range j.Translations {
+		if j.Translations[i].Lang == lang {
+			return &j.Translations[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// excerptLength is the length of Job.Excerpt, in characters.
+const excerptLength = 200
+
+// markdown passes raw HTML through to descriptionPolicy instead of
+// dropping it, so allowed tags such as <u> keep working.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.Linkify, extension.Strikethrough, extension.Table),
+	goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+)
+
+// descriptionPolicy is the allow-list of HTML kept in rendered
+// descriptions; any other element is removed, with the content of script
+// and style elements.
+var descriptionPolicy = func() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "hr", "ul", "ol", "li", "strong", "b", "em", "i", "u", "del",
+		"h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "code", "pre",
+		"table", "thead", "tbody", "tr", "th", "td")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	p.AllowStandardURLs()
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}()
+
+var textPolicy = bluemonday.StrictPolicy()
+
+// Render fills DescriptionHTML and Excerpt of the job and its translations
+// from the Markdown descriptions unless they are already set, as they are
+// for jobs read from the cache.
+func (j *Job) Render() {
+	render(j.Description, &j.DescriptionHTML, &j.Excerpt)
+	for i := range j.Translations {
+		t := &j.Translations[i]
+		render(t.Description, &t.DescriptionHTML, &t.Excerpt)
+	}
+}
+
+// render leaves the fields alone when the description renders to nothing,
+// so that rendering a job already rendered never writes to it; jobs from
+// one load are shared by concurrent requests.
+func render(description string, rendered, short *string) {
+	if *rendered != "" || description == "" {
+		return
+	}
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(description), &buf); err != nil {
+		// Rendering only fails on write errors, which a buffer does not
+		// return; fall back to the escaped source regardless.
+		buf.Reset()
+		buf.WriteString("<p>" + html.EscapeString(description) + "</p>")
+	}
+	if sanitized := descriptionPolicy.Sanitize(buf.String()); sanitized != "" {
+		*rendered = sanitized
+		*short = excerpt(sanitized)
+	}
+}
+
+// excerpt is the plain text of the rendered description on one line, cut
+// at a word boundary.
+func excerpt(rendered string) string {
+	text := strings.Join(strings.Fields(html.UnescapeString(textPolicy.Sanitize(rendered))), " ")
+	if utf8.RuneCountInString(text) <= excerptLength {
+		return text
+	}
+	cut := string([]rune(text)[:excerptLength])
+	if i := strings.LastIndexByte(cut, ' '); i > excerptLength/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
+
+func renderAll(jobs []Job) {
+	for i := range jobs {
+		jobs[i].Render()
+	}
+}
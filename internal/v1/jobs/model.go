@@ -1,5 +1,17 @@
 package jobs
 
+// Actions checked against the RBAC policy. The ":any" variants allow acting
+// on jobs the caller does not own.
+const (
+	ActionRead         = "jobs:read"
+	ActionReadInactive = "jobs:read:inactive"
+	ActionCreate       = "jobs:create"
+	ActionUpdate       = "jobs:update"
+	ActionUpdateAny    = "jobs:update:any"
+	ActionDelete       = "jobs:delete"
+	ActionDeleteAny    = "jobs:delete:any"
+)
+
 type Job struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
 	Title       string `json:"title"`
@@ -9,8 +21,39 @@ type Job struct {
 	State       string `json:"state"`
 	Status      bool   `json:"status"`
 	CreatedAt   int64  `json:"created_at"`
+	OwnerID     string `gorm:"size:255;index" json:"owner_id"`
+
+	// Description is Markdown. DescriptionHTML and Excerpt are rendered
+	// from it and not stored in the database; Render fills them before the
+	// job is cached, so cache hits skip rendering.
+	DescriptionHTML string `gorm:"-" json:"description_html,omitempty"`
+	Excerpt         string `gorm:"-" json:"excerpt,omitempty"`
+
+	// Lang is the BCP 47 tag of Title and Description; jobs posted before
+	// translations existed leave it empty and are in the default language.
+	// Translations hold the same text in other languages.
+	Lang         string        `gorm:"size:35" json:"lang"`
+	Translations []Translation `gorm:"foreignKey:JobID" json:"translations,omitempty"`
 }
 
 func (Job) TableName() string {
 	return "jobs"
 }
+
+// Translation is the title and description of a job in one more language.
+type Translation struct {
+	ID          uint   `gorm:"primaryKey" json:"-"`
+	JobID       uint   `gorm:"uniqueIndex:idx_job_translations_job_lang" json:"-"`
+	Lang        string `gorm:"size:35;uniqueIndex:idx_job_translations_job_lang" json:"lang"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CreatedAt   int64  `json:"created_at"`
+	UpdatedAt   int64  `json:"updated_at"`
+
+	DescriptionHTML string `gorm:"-" json:"description_html,omitempty"`
+	Excerpt         string `gorm:"-" json:"excerpt,omitempty"`
+}
+
+func (Translation) TableName() string {
+	return "job_translations"
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import "golang.org/x/text/language"
+
+// subdivisions are the two-letter codes of US states and Canadian
+// provinces, which postings use in place of the country.
+var subdivisions = map[string]bool{
+	"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true, "CO": true, "CT": true, "DE": true,
+	"DC": true, "FL": true, "GA": true, "HI": true, "ID": true, "IL": true, "IN": true, "IA": true,
+	"KS": true, "KY": true, "LA": true, "ME": true, "MD": true, "MA": true, "MI": true, "MN": true,
+	"MS": true, "MO": true, "MT": true, "NE": true, "NV": true, "NH": true, "NJ": true, "NM": true,
+	"NY": true, "NC": true, "ND": true, "OH": true, "OK": true, "OR": true, "PA": true, "RI": true,
+	"SC": true, "SD": true, "TN": true, "TX": true, "UT": true, "VT": true, "VA": true, "WA": true,
+	"WV": true, "WI": true, "WY": true,
+
+	"AB": true, "BC": true, "MB": true, "NB": true, "NL": true, "NS": true, "NT": true, "NU": true,
+	"ON": true, "PE": true, "QC": true, "SK": true, "YT": true,
+}
+
+// ValidRegion reports whether code is an upper-case ISO 3166-1 alpha-2
+// country code or a US state or Canadian province code.
+func ValidRegion(code string) bool {
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return false
+	}
+	if subdivisions[code] {
+		return true
+	}
+	region, err := language.ParseRegion(code)
+	return err == nil && region.IsCountry() && region.String() == code
+}
@@ -2,20 +2,35 @@ package jobs
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"log/slog"
+	"time"
 
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var tracer = otel.Tracer("github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs")
+
 type JobRepository interface {
 	Create(ctx context.Context, job *Job) error
-	List(ctx context.Context, offset, limit int) ([]Job, int64, error)
+	List(ctx context.Context, includeInactive bool, offset, limit int) ([]Job, int64, error)
 	Delete(ctx context.Context, id uint) error
-	Search(ctx context.Context, query string, offset, limit int) ([]Job, int64, error)
+	Search(ctx context.Context, includeInactive bool, query string, offset, limit int) ([]Job, int64, error)
 	GetByID(ctx context.Context, id uint) (*Job, error)
 	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	// PutTranslation creates or replaces the translation of the job into
+	// translation.Lang and reports whether it was created.
+	PutTranslation(ctx context.Context, jobID uint, translation *Translation) (bool, error)
+	DeleteTranslation(ctx context.Context, jobID uint, lang string) error
 }
 
+// GormJobRepository writes job lifecycle events to the outbox in the same
+// transaction as the change; the outbox relay publishes them.
 type GormJobRepository struct {
 	db    *gorm.DB
 	cache *JobCache
@@ -26,115 +41,253 @@ func NewGormJobRepository(db *gorm.DB, cache *JobCache) JobRepository {
 }
 
 func (r *GormJobRepository) Create(ctx context.Context, job *Job) error {
-	err := r.db.Create(job).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return enqueueEvent(tx, EventJobCreated, *job)
+	})
 	if err != nil {
 		return err
 	}
 
-	r.cache.SetJob(ctx, job)
-
-	r.cache.InvalidateJobsList(ctx)
+	job.Render()
+	logCacheError(ctx, "set job", r.cache.SetJob(ctx, job))
+	logCacheError(ctx, "invalidate job lists", r.cache.InvalidateJobsList(ctx))
 
 	return nil
 }
 
-func (r *GormJobRepository) List(ctx context.Context, offset, limit int) ([]Job, int64, error) {
-	page := (offset / limit) + 1
+func (r *GormJobRepository) List(ctx context.Context, includeInactive bool, offset, limit int) ([]Job, int64, error) {
+	ctx, span := tracer.Start(ctx, "jobs.List")
+	defer span.End()
 
-	// Try to get from cache first
-	jobs, total, err := r.cache.GetJobsList(ctx, page, limit)
-	fmt.Println(jobs)
-	if err == nil {
-		return jobs, total, nil
-	}
+	page := (offset / limit) + 1
 
-	// If not in cache, get from database
-	var dbJobs []Job
-	var dbTotal int64
-	r.db.Model(&Job{}).Count(&dbTotal)
-	err = r.db.Order("created_at desc").Offset(offset).Limit(limit).Find(&dbJobs).Error
+	result, hit, err := r.cache.fetchJobsList(ctx, includeInactive, page, limit, func(ctx context.Context) (jobPage, error) {
+		var result jobPage
+		dbq := scopeStatus(r.db.WithContext(ctx).Model(&Job{}), includeInactive)
+		if err := dbq.Count(&result.Total).Error; err != nil {
+			return result, err
+		}
+		err := dbq.Order("created_at desc").Offset(offset).Limit(limit).Preload("Translations", byLang).Find(&result.Jobs).Error
+		if err != nil {
+			return result, err
+		}
+		renderAll(result.Jobs)
+		return result, nil
+	})
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
 	if err != nil {
 		return nil, 0, err
 	}
-
-	r.cache.SetJobsList(ctx, page, limit, dbJobs, dbTotal)
-
-	return dbJobs, dbTotal, nil
+	return result.Jobs, result.Total, nil
 }
 
 func (r *GormJobRepository) Delete(ctx context.Context, id uint) error {
-	err := r.db.Delete(&Job{}, id).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		if err := tx.First(&job, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Job{}, id).Error; err != nil {
+			return err
+		}
+		return enqueueEvent(tx, EventJobDeleted, job)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Deleting a missing job is a no-op, as before.
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	// Invalidate caches
-	r.cache.InvalidateJob(ctx, id)
-	r.cache.InvalidateJobsList(ctx)
-	r.cache.InvalidateJobsSearch(ctx)
+	r.invalidate(ctx, id)
 
 	return nil
 }
 
-func (r *GormJobRepository) Search(ctx context.Context, query string, offset, limit int) ([]Job, int64, error) {
-	page := (offset / limit) + 1
+func (r *GormJobRepository) Search(ctx context.Context, includeInactive bool, query string, offset, limit int) ([]Job, int64, error) {
+	ctx, span := tracer.Start(ctx, "jobs.Search")
+	defer span.End()
 
-	// Try to get from cache first
-	jobs, total, err := r.cache.GetJobsSearch(ctx, query, page, limit)
-	if err == nil {
-		return jobs, total, nil
-	}
+	page := (offset / limit) + 1
 
-	// If not in cache, get from database
-	var dbJobs []Job
-	var dbTotal int64
-	q := "%" + query + "%"
-	dbq := r.db.Model(&Job{}).Where(
-		"title LIKE ? OR description LIKE ? OR company LIKE ? OR city LIKE ? OR state LIKE ?",
-		q, q, q, q, q,
-	)
-	dbq.Count(&dbTotal)
-	err = dbq.Order("created_at desc").Offset(offset).Limit(limit).Find(&dbJobs).Error
+	result, hit, err := r.cache.fetchJobsSearch(ctx, includeInactive, query, page, limit, func(ctx context.Context) (jobPage, error) {
+		var result jobPage
+		q := "%" + query + "%"
+		translated := r.db.WithContext(ctx).Model(&Translation{}).Select("job_id").Where("title LIKE ? OR description LIKE ?", q, q)
+		dbq := scopeStatus(r.db.WithContext(ctx).Model(&Job{}), includeInactive).Where(
+			"title LIKE ? OR description LIKE ? OR company LIKE ? OR city LIKE ? OR state LIKE ? OR id IN (?)",
+			q, q, q, q, q, translated,
+		)
+		if err := dbq.Count(&result.Total).Error; err != nil {
+			return result, err
+		}
+		err := dbq.Order("created_at desc").Offset(offset).Limit(limit).Preload("Translations", byLang).Find(&result.Jobs).Error
+		if err != nil {
+			return result, err
+		}
+		renderAll(result.Jobs)
+		return result, nil
+	})
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
 	if err != nil {
 		return nil, 0, err
 	}
+	return result.Jobs, result.Total, nil
+}
 
-	// Cache the result
-	r.cache.SetJobsSearch(ctx, query, page, limit, dbJobs, dbTotal)
+func scopeStatus(q *gorm.DB, includeInactive bool) *gorm.DB {
+	if includeInactive {
+		return q
+	}
+	return q.Where("status = ?", true)
+}
 
-	return dbJobs, dbTotal, nil
+func byLang(q *gorm.DB) *gorm.DB {
+	return q.Order("lang")
 }
 
 func (r *GormJobRepository) GetByID(ctx context.Context, id uint) (*Job, error) {
-	// Try to get from cache first
-	job, err := r.cache.GetJob(ctx, id)
-	if err == nil {
+	ctx, span := tracer.Start(ctx, "jobs.GetByID")
+	defer span.End()
+
+	job, hit, err := r.cache.fetchJob(ctx, id, func(ctx context.Context) (Job, error) {
+		var job Job
+		if err := r.db.WithContext(ctx).Preload("Translations", byLang).First(&job, id).Error; err != nil {
+			return job, apperr.FromDB(err, "Job")
+		}
+		job.Render()
 		return job, nil
+	})
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
+	if err != nil {
+		return nil, err
 	}
+	return &job, nil
+}
 
-	// If not in cache, get from database
-	var dbJob Job
-	err = r.db.First(&dbJob, id).Error
+func (r *GormJobRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before Job
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&Job{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		var job Job
+		if err := tx.Preload("Translations", byLang).First(&job, id).Error; err != nil {
+			return err
+		}
+		if err := enqueueEvent(tx, EventJobUpdated, job); err != nil {
+			return err
+		}
+		if job.Status != before.Status {
+			return enqueueEvent(tx, EventJobStatusChanged, job)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return apperr.FromDB(err, "Job")
 	}
 
-	// Cache the job
-	r.cache.SetJob(ctx, &dbJob)
+	r.invalidate(ctx, id)
 
-	return &dbJob, nil
+	return nil
 }
 
-func (r *GormJobRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
-	err := r.db.Model(&Job{}).Where("id = ?", id).Updates(updates).Error
+// PutTranslation publishes EventJobUpdated with every translation of the
+// job, like Update.
+func (r *GormJobRepository) PutTranslation(ctx context.Context, jobID uint, translation *Translation) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobID).Error; err != nil {
+			return err
+		}
+		now := time.Now().Unix()
+		var existing Translation
+		err := tx.Where("job_id = ? AND lang = ?", jobID, translation.Lang).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+			translation.JobID = jobID
+			translation.CreatedAt = now
+			translation.UpdatedAt = now
+			err = tx.Create(translation).Error
+		case err == nil:
+			existing.Title = translation.Title
+			existing.Description = translation.Description
+			existing.UpdatedAt = now
+			err = tx.Save(&existing).Error
+			*translation = existing
+		}
+		if err != nil {
+			return err
+		}
+		return enqueueUpdated(tx, jobID)
+	})
+	if err != nil {
+		return false, apperr.FromDB(err, "Job")
+	}
+
+	r.invalidate(ctx, jobID)
+
+	return created, nil
+}
+
+func (r *GormJobRepository) DeleteTranslation(ctx context.Context, jobID uint, lang string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobID).Error; err != nil {
+			return apperr.FromDB(err, "Job")
+		}
+		result := tx.Where("job_id = ? AND lang = ?", jobID, lang).Delete(&Translation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperr.NotFound("Translation not found")
+		}
+		return enqueueUpdated(tx, jobID)
+	})
 	if err != nil {
 		return err
 	}
 
-	// Invalidate caches
-	r.cache.InvalidateJob(ctx, id)
-	r.cache.InvalidateJobsList(ctx)
-	r.cache.InvalidateJobsSearch(ctx)
+	r.invalidate(ctx, jobID)
 
 	return nil
 }
+
+func enqueueUpdated(tx *gorm.DB, jobID uint) error {
+	var job Job
+	if err := tx.Preload("Translations", byLang).First(&job, jobID).Error; err != nil {
+		return err
+	}
+	return enqueueEvent(tx, EventJobUpdated, job)
+}
+
+func (r *GormJobRepository) invalidate(ctx context.Context, id uint) {
+	logCacheError(ctx, "invalidate job", r.cache.InvalidateJob(ctx, id))
+	logCacheError(ctx, "invalidate job lists", r.cache.InvalidateJobsList(ctx))
+	logCacheError(ctx, "invalidate job searches", r.cache.InvalidateJobsSearch(ctx))
+}
+
+// logCacheError records a failed cache write or invalidation. The request
+// still succeeds; stale entries expire with their TTL.
+func logCacheError(ctx context.Context, op string, err error) {
+	if err != nil {
+		slog.WarnContext(ctx, "jobs: cache operation failed", "op", op, "error", err)
+	}
+}
+
+// logCacheMiss records cache read failures other than a plain miss.
+func logCacheMiss(ctx context.Context, op string, err error) {
+	if !db.IsNil(err) {
+		slog.WarnContext(ctx, "jobs: cache read failed", "op", op, "error", err)
+	}
+}
@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	mathrand "math/rand/v2"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+)
+
+// earlyRefreshBeta scales how early entries are refreshed; values above 1
+// refresh earlier. 1 is the value recommended for XFetch in "Optimal
+// Probabilistic Cache Stampede Prevention" (Vattani et al., 2015).
+const earlyRefreshBeta = 1.0
+
+// lockPollInterval is how often a replica waiting for the lock holder
+// checks whether the entry has been filled.
+const lockPollInterval = 25 * time.Millisecond
+
+// StampedeOptions keep a popular entry that expires or is invalidated from
+// sending every concurrent request to MySQL. Within a process, concurrent
+// misses of a key always share one load.
+//
+// With Lock, the replica that misses first takes a Redis lock on the key
+// for up to LockTTL. The others poll the cache for up to LockWait, then
+// load the entry themselves.
+//
+// With EarlyRefresh, a hit may refresh the entry before it expires. The
+// chance grows as expiry nears, relative to how long loading the entry
+// took. Entries read often are thus refreshed before they expire, and
+// entries read rarely expire as before.
+type StampedeOptions struct {
+	Lock         bool
+	LockTTL      time.Duration
+	LockWait     time.Duration
+	EarlyRefresh bool
+}
+
+// SetStampede applies to lookups from now on.
+func (c *JobCache) SetStampede(opts StampedeOptions) {
+	c.stampede.Store(&opts)
+}
+
+// fetch returns the entry cached under key. On a miss, load is called and
+// its result cached for ttl. hit reports whether the value came from the
+// cache.
+func fetch[T any](ctx context.Context, c *JobCache, family, key string, ttl time.Duration, load func(context.Context) (T, error)) (value T, hit bool, err error) {
+	remaining, err := c.redis.GetWithTTL(ctx, key, &value)
+	recordLookup(family, err)
+	if err == nil {
+		if !c.stampede.Load().EarlyRefresh || !c.expiresSoon(family, remaining) {
+			return value, true, nil
+		}
+		metrics.CacheEarlyRefreshes.WithLabelValues(family).Inc()
+		fresh, err := share(ctx, c, family, key, ttl, load)
+		if err != nil {
+			// The cached entry has not expired yet.
+			logCacheError(ctx, "refresh "+family, err)
+			return value, true, nil
+		}
+		return fresh, false, nil
+	}
+	logCacheMiss(ctx, "get "+family, err)
+
+	value, err = share(ctx, c, family, key, ttl, load)
+	return value, false, err
+}
+
+// share runs fill once for all concurrent callers asking for key. The
+// load runs on after a caller gives up, so that the others still get its
+// result.
+func share[T any](ctx context.Context, c *JobCache, family, key string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	result := c.flights.DoChan(key, func() (interface{}, error) {
+		return fill(context.WithoutCancel(ctx), c, family, key, ttl, load)
+	})
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			var zero T
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
+
+// fill loads the entry and caches it. With StampedeOptions.Lock it first
+// takes the lock, or waits for the replica that holds it. Redis errors
+// never fail the load.
+//
+// The generations of the key are read before the load, and the entry is
+// only written if they are unchanged. Otherwise it was invalidated while
+// loading and the result may predate the write that invalidated it; it
+// is still returned, but not cached.
+func fill[T any](ctx context.Context, c *JobCache, family, key string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	if opts := c.stampede.Load(); opts.Lock {
+		lockKey, token := "lock:"+key, lockToken()
+		locked, err := c.redis.TryLock(ctx, lockKey, token, opts.LockTTL)
+		switch {
+		case err != nil:
+			logCacheError(ctx, "lock "+family, err)
+		case locked:
+			defer func() { logCacheError(ctx, "unlock "+family, c.redis.Unlock(ctx, lockKey, token)) }()
+		default:
+			if value, ok := waitForFill[T](ctx, c, key, opts.LockWait); ok {
+				metrics.CacheFills.WithLabelValues(family, "replica").Inc()
+				return value, nil
+			}
+		}
+	}
+
+	genKeys := generationKeys(family, key)
+	gens, genErr := c.redis.Generations(ctx, genKeys...)
+	logCacheError(ctx, "get generation "+family, genErr)
+
+	start := time.Now()
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+	c.loadTimes[family].Store(int64(time.Since(start)))
+	metrics.CacheFills.WithLabelValues(family, "database").Inc()
+	if genErr == nil {
+		_, err := c.redis.SetIfGenerations(ctx, key, value, ttl, genKeys, gens)
+		logCacheError(ctx, "set "+family, err)
+	}
+	return value, nil
+}
+
+// waitForFill polls the cache until key appears or wait has passed.
+func waitForFill[T any](ctx context.Context, c *JobCache, key string, wait time.Duration) (T, bool) {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	poll := time.NewTicker(lockPollInterval)
+	defer poll.Stop()
+	for {
+		select {
+		case <-deadline.C:
+			var zero T
+			return zero, false
+		case <-poll.C:
+			var value T
+			if err := c.redis.Get(ctx, key, &value); err == nil {
+				return value, true
+			}
+		}
+	}
+}
+
+// expiresSoon is the XFetch test: it is true with a probability that rises
+// as the remaining TTL approaches the time the last load of the family
+// took.
+func (c *JobCache) expiresSoon(family string, remaining time.Duration) bool {
+	took := c.loadTimes[family].Load()
+	if took <= 0 || remaining < 0 {
+		return false
+	}
+	// 1-Float64 is in (0, 1], so the logarithm is finite.
+	return float64(remaining) <= float64(took)*earlyRefreshBeta*-math.Log(1-mathrand.Float64())
+}
+
+func lockToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/pkg/jobfeed"
+)
+
+var cloudEventTypes = map[EventType]string{
+	EventJobCreated:       jobfeed.TypeJobCreated,
+	EventJobUpdated:       jobfeed.TypeJobUpdated,
+	EventJobDeleted:       jobfeed.TypeJobDeleted,
+	EventJobStatusChanged: jobfeed.TypeJobStatusChanged,
+}
+
+// StreamPublisher appends job events to a Redis Stream as CloudEvents 1.0
+// JSON envelopes. The CloudEvents id is the job event ID, so consumers can
+// drop duplicates caused by outbox redelivery.
+type StreamPublisher struct {
+	redis  *db.RedisClient
+	stream string
+	maxLen int64
+}
+
+func NewStreamPublisher(redis *db.RedisClient, stream string, maxLen int64) *StreamPublisher {
+	return &StreamPublisher{redis: redis, stream: stream, maxLen: maxLen}
+}
+
+func (p *StreamPublisher) Publish(ctx context.Context, event JobEvent) error {
+	ceType, ok := cloudEventTypes[event.Type]
+	if !ok {
+		return fmt.Errorf("stream: unknown event type %q", event.Type)
+	}
+	data, err := json.Marshal(event.Job)
+	if err != nil {
+		return err
+	}
+	envelope, err := json.Marshal(jobfeed.Event{
+		SpecVersion:     jobfeed.SpecVersion,
+		ID:              event.ID,
+		Source:          jobfeed.Source,
+		Type:            ceType,
+		Subject:         fmt.Sprintf("jobs/%d", event.Job.ID),
+		Time:            time.Unix(event.OccurredAt, 0).UTC(),
+		DataContentType: jobfeed.DataContentType,
+		Data:            data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.redis.XAdd(ctx, p.stream, p.maxLen, map[string]interface{}{
+		jobfeed.FieldType:  ceType,
+		jobfeed.FieldEvent: string(envelope),
+	})
+	if err != nil {
+		return fmt.Errorf("stream: append %s to %s: %w", event.Type, p.stream, err)
+	}
+	return nil
+}
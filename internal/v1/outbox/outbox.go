@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Message is a domain event waiting to be published. It is written in the
+// same transaction as the change it describes, so an event exists if and
+// only if the change was committed. A message that keeps failing is marked
+// Failed as well as processed, so it no longer holds back later ones.
+type Message struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	EventID       string `gorm:"size:64;uniqueIndex" json:"event_id"`
+	AggregateType string `gorm:"size:64" json:"aggregate_type"`
+	AggregateID   uint   `json:"aggregate_id"`
+	EventType     string `gorm:"size:64" json:"event_type"`
+	Payload       string `gorm:"type:text" json:"payload"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `gorm:"type:text" json:"last_error"`
+	Failed        bool   `json:"failed"`
+	ClaimedUntil  *int64 `json:"claimed_until"`
+	NextAttemptAt int64  `json:"next_attempt_at"`
+	CreatedAt     int64  `json:"created_at"`
+	ProcessedAt   *int64 `gorm:"index" json:"processed_at"`
+}
+
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// Enqueue adds a message to the outbox using tx, which must be the
+// transaction that performs the change the event describes.
+func Enqueue(tx *gorm.DB, eventID, aggregateType string, aggregateID uint, eventType string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&Message{
+		EventID:       eventID,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(body),
+		CreatedAt:     time.Now().Unix(),
+	}).Error
+}
@@ -0,0 +1,221 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	pollInterval    = time.Second
+	batchSize       = 100
+	retention       = 7 * 24 * time.Hour
+	cleanupInterval = time.Hour
+
+	// MaxAttempts is how often a message is published before it is marked
+	// failed and skipped. With the backoff below, that is about two hours
+	// of failures, so outages of the stream or the database do not lose
+	// events.
+	MaxAttempts = 20
+	baseBackoff = time.Second
+	maxBackoff  = 10 * time.Minute
+
+	// claimLease is how long a replica has to publish the batch it claimed
+	// before another may take it over.
+	claimLease = 5 * time.Minute
+
+	// errLockNowait is MySQL's ER_LOCK_NOWAIT: another relay holds the rows.
+	errLockNowait = 3572
+)
+
+// Handler publishes a single message. Messages can be delivered more than
+// once (for example when the process dies between publishing and marking
+// the row processed), so handlers must be idempotent on EventID.
+type Handler func(ctx context.Context, msg Message) error
+
+// Relay publishes pending outbox messages in ID order. Each batch is
+// claimed with a lease before it is published, so when several replicas
+// run a relay only one of them publishes at a time and the others skip the
+// round instead of publishing the same rows or overtaking it.
+type Relay struct {
+	db      *gorm.DB
+	handler Handler
+}
+
+func NewRelay(db *gorm.DB, handler Handler) *Relay {
+	return &Relay{db: db, handler: handler}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := r.RelayBatch(ctx)
+				metrics.OutboxMessagesRelayed.Add(float64(n))
+				if err != nil {
+					metrics.OutboxRelayErrors.Inc()
+					slog.ErrorContext(ctx, "outbox: relay failed", "error", err)
+					break
+				}
+				if n < batchSize {
+					break
+				}
+			}
+			if time.Since(lastCleanup) > cleanupInterval {
+				r.cleanup(ctx)
+				lastCleanup = time.Now()
+			}
+		}
+	}
+}
+
+// RelayBatch publishes up to one batch of pending messages and returns how
+// many were marked processed. Publishing stops at the first failure so that
+// later messages never overtake an earlier one, unless the message has
+// failed MaxAttempts times: it is then marked failed and processed, and the
+// batch goes on.
+//
+// The batch is claimed first and published after the claim is committed,
+// so no row locks are held while handlers run.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	pending, leaseUntil, err := r.claim(ctx)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errLockNowait {
+		// Another replica is claiming this batch.
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for i := range pending {
+		msg := &pending[i]
+		if err := r.handler(ctx, *msg); err != nil {
+			if msg.Attempts+1 < MaxAttempts {
+				return processed, r.release(ctx, pending[i:], leaseUntil, err)
+			}
+			err := r.db.WithContext(ctx).Model(&Message{}).Where("id = ?", msg.ID).Updates(map[string]interface{}{
+				"attempts":     gorm.Expr("attempts + 1"),
+				"last_error":   err.Error(),
+				"failed":       true,
+				"processed_at": time.Now().Unix(),
+			}).Error
+			if err != nil {
+				return processed, err
+			}
+			metrics.OutboxMessagesFailed.Inc()
+			slog.WarnContext(ctx, "outbox: message failed", "id", msg.ID, "event_id", msg.EventID,
+				"event_type", msg.EventType, "attempts", msg.Attempts+1)
+			continue
+		}
+		res := r.db.WithContext(ctx).Model(&Message{}).
+			Where("id = ? AND processed_at IS NULL", msg.ID).
+			Update("processed_at", time.Now().Unix())
+		if res.Error != nil {
+			return processed, res.Error
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// claim leases the next batch of pending messages until the returned time.
+// The rows are locked with SELECT ... FOR UPDATE NOWAIT only while the
+// lease is written. When a row of the batch is leased by another replica,
+// or is waiting out its backoff, nothing is claimed: publishing the rows
+// after it would overtake it. A lease that runs out, because its replica
+// died, is taken over.
+func (r *Relay) claim(ctx context.Context) ([]Message, int64, error) {
+	now := time.Now()
+	leaseUntil := now.Add(claimLease).Unix()
+	var pending []Message
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("processed_at IS NULL").
+			Order("id asc").
+			Limit(batchSize).
+			Find(&pending).Error
+		if err != nil {
+			return err
+		}
+		ids := make([]uint, len(pending))
+		for i, msg := range pending {
+			if (msg.ClaimedUntil != nil && *msg.ClaimedUntil > now.Unix()) || msg.NextAttemptAt > now.Unix() {
+				pending = nil
+				return nil
+			}
+			ids[i] = msg.ID
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&Message{}).Where("id IN ?", ids).Update("claimed_until", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return pending, leaseUntil, nil
+}
+
+// release records the failure of the first of the unpublished messages,
+// delays its next attempt by Backoff, and gives up the lease on all of
+// them, so that they are retried in order. Rows whose lease has been taken over are left alone. It runs even
+// when ctx has been cancelled by shutdown, so that other replicas need not
+// wait for the lease to run out; an interrupted publish is not counted as
+// an attempt.
+func (r *Relay) release(ctx context.Context, unpublished []Message, leaseUntil int64, cause error) error {
+	ids := make([]uint, len(unpublished))
+	for i := range unpublished {
+		ids[i] = unpublished[i].ID
+	}
+	interrupted := ctx.Err() != nil
+	return r.db.WithContext(context.WithoutCancel(ctx)).Transaction(func(tx *gorm.DB) error {
+		if !interrupted {
+			err := tx.Model(&Message{}).Where("id = ? AND claimed_until = ?", ids[0], leaseUntil).Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"last_error":      cause.Error(),
+				"next_attempt_at": time.Now().Add(Backoff(unpublished[0].Attempts + 1)).Unix(),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Message{}).
+			Where("id IN ? AND claimed_until = ? AND processed_at IS NULL", ids, leaseUntil).
+			Update("claimed_until", nil).Error
+	})
+}
+
+// Backoff returns the delay before the next attempt after the given number
+// of failed attempts: 1s doubled per attempt, capped at 10 minutes.
+func Backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+func (r *Relay) cleanup(ctx context.Context) {
+	cutoff := time.Now().Add(-retention).Unix()
+	// Failed messages are kept for inspection.
+	err := r.db.WithContext(ctx).
+		Where("processed_at IS NOT NULL AND processed_at < ? AND failed = ?", cutoff, false).
+		Delete(&Message{}).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "outbox: cleanup failed", "error", err)
+	}
+}
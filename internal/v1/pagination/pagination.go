@@ -0,0 +1,30 @@
+package pagination
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limits bounds the page size of list endpoints.
+type Limits struct {
+	Default int
+	Max     int
+}
+
+// Parse reads the page and limit query parameters. Missing or invalid values
+// fall back to page 1 and the default size; larger sizes are capped at Max.
+func (l Limits) Parse(c *gin.Context) (page, limit int) {
+	page, _ = strconv.Atoi(c.Query("page"))
+	limit, _ = strconv.Atoi(c.Query("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = l.Default
+	}
+	if limit > l.Max {
+		limit = l.Max
+	}
+	return page, limit
+}
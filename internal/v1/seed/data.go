@@ -0,0 +1,129 @@
+package seed
+
+// role is a job family with its title in both languages and the skills
+// its descriptions ask for.
+type role struct {
+	en, tr string
+	skills []string
+}
+
+var roles = []role{
+	{"Go Developer", "Go Geliştirici", []string{"Go", "gRPC", "PostgreSQL", "Kubernetes", "Redis"}},
+	{"Backend Engineer", "Backend Mühendisi", []string{"Java", "Spring Boot", "MySQL", "Kafka", "Docker"}},
+	{"Frontend Developer", "Frontend Geliştirici", []string{"React", "TypeScript", "Next.js", "CSS", "Jest"}},
+	{"Full Stack Developer", "Full Stack Geliştirici", []string{"Node.js", "React", "MongoDB", "TypeScript", "AWS"}},
+	{"Mobile Developer", "Mobil Uygulama Geliştirici", []string{"Kotlin", "Swift", "Flutter", "Firebase", "REST"}},
+	{"DevOps Engineer", "DevOps Mühendisi", []string{"Terraform", "Kubernetes", "GitLab CI", "AWS", "Prometheus"}},
+	{"Data Scientist", "Veri Bilimci", []string{"Python", "pandas", "scikit-learn", "SQL", "Spark"}},
+	{"Data Analyst", "Veri Analisti", []string{"SQL", "Power BI", "Excel", "Python", "Tableau"}},
+	{"QA Engineer", "Test Mühendisi", []string{"Selenium", "Cypress", "Postman", "JMeter", "Java"}},
+	{"Product Manager", "Ürün Yöneticisi", []string{"Jira", "roadmapping", "A/B testing", "SQL", "Figma"}},
+	{"UX Designer", "UX Tasarımcı", []string{"Figma", "user research", "prototyping", "Adobe XD", "design systems"}},
+	{"Security Engineer", "Siber Güvenlik Uzmanı", []string{"SIEM", "penetration testing", "ISO 27001", "Linux", "OWASP"}},
+	{"Accountant", "Muhasebe Uzmanı", []string{"Logo Tiger", "SAP", "Excel", "tax law", "e-invoicing"}},
+	{"Sales Representative", "Satış Temsilcisi", []string{"Salesforce", "CRM", "negotiation", "B2B sales", "English"}},
+	{"HR Specialist", "İnsan Kaynakları Uzmanı", []string{"recruitment", "payroll", "labour law", "HRIS", "onboarding"}},
+	{"Customer Support Agent", "Müşteri Temsilcisi", []string{"Zendesk", "live chat", "English", "CRM", "call center"}},
+	{"Logistics Specialist", "Lojistik Uzmanı", []string{"SAP MM", "customs", "warehouse management", "Excel", "incoterms"}},
+	{"Mechanical Engineer", "Makine Mühendisi", []string{"SolidWorks", "AutoCAD", "lean manufacturing", "GD&T", "FMEA"}},
+	{"Electrical Engineer", "Elektrik Mühendisi", []string{"PLC", "SCADA", "EPLAN", "AutoCAD Electrical", "IEC standards"}},
+	{"Digital Marketing Specialist", "Dijital Pazarlama Uzmanı", []string{"Google Ads", "SEO", "Meta Ads", "GA4", "content strategy"}},
+}
+
+type level struct {
+	en, tr   string
+	minYears int
+}
+
+var levels = []level{
+	{"Intern", "Stajyer", 0},
+	{"Junior", "Junior", 0},
+	{"", "", 2},
+	{"Senior", "Kıdemli", 5},
+	{"Lead", "Lead", 7},
+}
+
+// levelWeights makes mid and senior positions the most common.
+var levelWeights = []int{1, 3, 5, 4, 1}
+
+var companies = []string{
+	"Anadolu Yazılım A.Ş.", "Boğaziçi Teknoloji", "Ege Bilişim", "Marmara Lojistik", "Karadeniz Enerji",
+	"Kapadokya Dijital", "Toros Otomotiv", "Galata Finans", "Pera Labs", "Atlas Perakende",
+	"Yeditepe Sağlık", "Kuzey Holding", "Akdeniz Turizm", "Trakya Gıda", "Dicle Tekstil",
+	"Northwind Systems", "Blue Harbor Software", "Brightline Analytics", "Cedar Cloud", "Summit Payments",
+	"Lighthouse Games", "Orbit Mobility", "Silverleaf Health", "Quantum Retail", "Helix Security",
+}
+
+type place struct {
+	city, state string
+}
+
+var turkishPlaces = []place{
+	{"Istanbul", "TR"}, {"Ankara", "TR"}, {"Izmir", "TR"}, {"Bursa", "TR"}, {"Antalya", "TR"},
+	{"Kocaeli", "TR"}, {"Konya", "TR"}, {"Gaziantep", "TR"}, {"Eskişehir", "TR"}, {"Kayseri", "TR"},
+	{"Adana", "TR"}, {"Trabzon", "TR"}, {"Mersin", "TR"}, {"Denizli", "TR"}, {"Samsun", "TR"},
+}
+
+// turkishWeights reflects how job postings concentrate in the largest
+// cities.
+var turkishWeights = []int{40, 14, 10, 6, 5, 4, 3, 3, 3, 2, 2, 2, 2, 2, 2}
+
+var foreignPlaces = []place{
+	{"Berlin", "DE"}, {"London", "GB"}, {"Amsterdam", "NL"}, {"Dublin", "IE"}, {"Warsaw", "PL"},
+	{"Austin", "TX"}, {"New York", "NY"}, {"Toronto", "ON"},
+}
+
+type phrases struct {
+	intro        string
+	duties       []string
+	requirements string
+	entryLevel   string
+	skills       string
+	benefits     []string
+}
+
+var english = phrases{
+	intro: "%s is looking for a new %s to join its team in %s.",
+	duties: []string{
+		"You will work closely with product and engineering teams to deliver new features.",
+		"You will own processes end to end, from planning to reporting.",
+		"You will help us improve quality and reliability across our services.",
+		"You will mentor colleagues and share knowledge across teams.",
+		"You will talk to customers and turn their feedback into improvements.",
+		"You will take part in planning and estimate the work of your team.",
+		"You will document decisions and keep our internal guides up to date.",
+	},
+	requirements: "We are looking for someone with at least %d years of relevant experience.",
+	entryLevel:   "No prior experience is required; we value curiosity and a willingness to learn.",
+	skills:       "Experience with %s and %s is expected; knowledge of %s is a plus.",
+	benefits: []string{
+		"We offer private health insurance and a yearly training budget.",
+		"Hybrid working with two office days a week.",
+		"Flexible hours, meal cards and a transport allowance.",
+		"Fully remote work is possible within the same time zone.",
+		"Stock options and a performance bonus twice a year.",
+	},
+}
+
+var turkish = phrases{
+	intro: "%s, %s ofisindeki ekibine katılacak %s arıyor.",
+	duties: []string{
+		"Ürün ve mühendislik ekipleriyle birlikte yeni özellikler geliştireceksiniz.",
+		"Süreçleri planlamadan raporlamaya kadar uçtan uca yöneteceksiniz.",
+		"Servislerimizin kalitesini ve güvenilirliğini artırmamıza yardımcı olacaksınız.",
+		"Ekip arkadaşlarınıza mentorluk yapacak ve bilgi paylaşımına katkı sağlayacaksınız.",
+		"Müşterilerle görüşerek geri bildirimlerini iyileştirmelere dönüştüreceksiniz.",
+		"Ekibinizin iş planlamasına ve tahminlemesine katılacaksınız.",
+		"Alınan kararları belgeleyecek ve iç dokümantasyonu güncel tutacaksınız.",
+	},
+	requirements: "İlgili alanda en az %d yıl deneyim sahibi adaylar arıyoruz.",
+	entryLevel:   "Deneyim şartı aranmamaktadır; öğrenmeye istekli adaylar arıyoruz.",
+	skills:       "%s ve %s deneyimi beklenmektedir; %s bilgisi tercih sebebidir.",
+	benefits: []string{
+		"Özel sağlık sigortası ve yıllık eğitim bütçesi sunuyoruz.",
+		"Haftada iki gün ofisten hibrit çalışma modeli.",
+		"Esnek çalışma saatleri, yemek kartı ve yol desteği.",
+		"Aynı saat diliminde tamamen uzaktan çalışma imkânı.",
+		"Yılda iki kez performans primi ve hisse opsiyonu.",
+	},
+}
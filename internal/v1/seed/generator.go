@@ -0,0 +1,158 @@
+// Package seed generates realistic synthetic jobs for load tests and demo
+// environments. The same options always generate the same jobs.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+	"gorm.io/gorm"
+)
+
+type Options struct {
+	Seed uint64
+	// End is the newest creation time; jobs are spread over the Months
+	// before it.
+	End    time.Time
+	Months int
+	// InactiveRatio is the share of closed jobs. Older jobs are more likely
+	// to be closed.
+	InactiveRatio float64
+}
+
+type Generator struct {
+	rng  *rand.Rand
+	opts Options
+	span int64
+}
+
+func NewGenerator(opts Options) *Generator {
+	end := opts.End.Unix()
+	start := opts.End.AddDate(0, -opts.Months, 0).Unix()
+	return &Generator{
+		rng:  rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15)),
+		opts: opts,
+		span: max(end-start, 1),
+	}
+}
+
+// Next generates one job. Turkish cities get mostly Turkish postings;
+// about one job in seven is abroad and written in English.
+func (g *Generator) Next() jobs.Job {
+	r := roles[g.rng.IntN(len(roles))]
+	lvl := levels[g.weighted(levelWeights)]
+	company := companies[g.rng.IntN(len(companies))]
+
+	var where place
+	turkishText := false
+	if g.rng.IntN(7) == 0 {
+		where = foreignPlaces[g.rng.IntN(len(foreignPlaces))]
+	} else {
+		where = turkishPlaces[g.weighted(turkishWeights)]
+		turkishText = g.rng.IntN(10) < 7
+	}
+
+	title, text, lang := join(lvl.en, r.en), english, "en"
+	if turkishText {
+		title, text, lang = join(lvl.tr, r.tr), turkish, "tr"
+	}
+
+	age := g.rng.Int64N(g.span)
+	closedChance := min(2*g.opts.InactiveRatio*float64(age)/float64(g.span), 1)
+
+	return jobs.Job{
+		Title:       title,
+		Description: g.describe(text, turkishText, company, title, where.city, lvl, r),
+		Company:     company,
+		City:        where.city,
+		State:       where.state,
+		Status:      g.rng.Float64() >= closedChance,
+		CreatedAt:   g.opts.End.Unix() - age,
+		Lang:        lang,
+	}
+}
+
+// Batch generates the next n jobs.
+func (g *Generator) Batch(n int) []jobs.Job {
+	batch := make([]jobs.Job, n)
+	for i := range batch {
+		batch[i] = g.Next()
+	}
+	return batch
+}
+
+func (g *Generator) describe(text phrases, turkishText bool, company, title, city string, lvl level, r role) string {
+	var paragraphs []string
+	if turkishText {
+		paragraphs = append(paragraphs, fmt.Sprintf(text.intro, company, city, title))
+	} else {
+		paragraphs = append(paragraphs, fmt.Sprintf(text.intro, company, title, city))
+	}
+
+	duties := g.pick(text.duties, 2+g.rng.IntN(2))
+	paragraphs = append(paragraphs, strings.Join(duties, " "))
+
+	requirements := text.entryLevel
+	if lvl.minYears > 0 {
+		requirements = fmt.Sprintf(text.requirements, lvl.minYears+g.rng.IntN(2))
+	}
+	skills := g.pick(r.skills, 3)
+	paragraphs = append(paragraphs, requirements+" "+fmt.Sprintf(text.skills, skills[0], skills[1], skills[2]))
+
+	paragraphs = append(paragraphs, strings.Join(g.pick(text.benefits, 1+g.rng.IntN(2)), " "))
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// pick returns n distinct items of list in random order.
+func (g *Generator) pick(list []string, n int) []string {
+	picked := make([]string, n)
+	for i, j := range g.rng.Perm(len(list))[:n] {
+		picked[i] = list[j]
+	}
+	return picked
+}
+
+// weighted returns an index into weights, chosen in proportion to them.
+func (g *Generator) weighted(weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	n := g.rng.IntN(total)
+	for i, w := range weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return len(weights) - 1
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + " " + name
+}
+
+// Insert generates count jobs and inserts them in multi-row batches of
+// batchSize. The rows are written directly, without outbox events, so
+// seeding does not trigger alerts or webhooks. progress, if set, is called
+// after each batch with the number of jobs inserted so far.
+func Insert(ctx context.Context, db *gorm.DB, g *Generator, count, batchSize int, progress func(inserted int)) error {
+	for inserted := 0; inserted < count; {
+		batch := g.Batch(min(batchSize, count-inserted))
+		if err := db.WithContext(ctx).Create(&batch).Error; err != nil {
+			return fmt.Errorf("seed: inserted %d of %d jobs: %w", inserted, count, err)
+		}
+		inserted += len(batch)
+		if progress != nil {
+			progress(inserted)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package users
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	Name     string `json:"name" binding:"required,max=255"`
+	Role     string `json:"role" binding:"required,oneof=employer candidate"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	// RefreshToken is only needed when the request has no access token.
+	RefreshToken string `json:"refresh_token"`
+}
+
+type UserResponse struct {
+	ID        uint   `json:"id"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type TokenResponse struct {
+	AccessToken  string        `json:"access_token"`
+	TokenType    string        `json:"token_type"`
+	ExpiresIn    int64         `json:"expires_in"`
+	RefreshToken string        `json:"refresh_token"`
+	User         *UserResponse `json:"user,omitempty"`
+}
+
+func toUserResponse(u *User) *UserResponse {
+	return &UserResponse{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+	}
+}
@@ -0,0 +1,161 @@
+package users
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	service *Service
+}
+
+func NewAuthHandler(service *Service) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// Register godoc
+// @Summary      Register an account
+// @Description  Create an employer or candidate account
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        user  body  RegisterRequest  true  "Account info"
+// @Success      201  {object}  UserResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	user, err := h.service.Register(c.Request.Context(), req)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toUserResponse(user))
+}
+
+// Login godoc
+// @Summary      Log in
+// @Description  Exchange email and password for an access token and a refresh token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body  LoginRequest  true  "Credentials"
+// @Success      200  {object}  TokenResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      429  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	// ClientIP only honours X-Forwarded-For from trusted proxies, so the
+	// client cannot choose the IP the throttle counts.
+	user, tokens, err := h.service.Login(c.Request.Context(), req.Email, req.Password, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		var throttled *TooManyAttemptsError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			problem.Abort(c, problem.TypeRateLimited, "Too many failed login attempts")
+		case errors.Is(err, ErrInvalidCredentials):
+			problem.Abort(c, problem.TypeUnauthorized, "Invalid email or password")
+		default:
+			problem.Error(c, err)
+		}
+		return
+	}
+	resp := toTokenResponse(tokens)
+	resp.User = toUserResponse(user)
+	c.JSON(http.StatusOK, resp)
+}
+
+// Refresh godoc
+// @Summary      Refresh an access token
+// @Description  Exchange a refresh token for a new access token; the refresh token is rotated
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token  body  RefreshRequest  true  "Refresh token"
+// @Success      200  {object}  TokenResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	tokens, err := h.service.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			problem.Abort(c, problem.TypeUnauthorized, "Invalid refresh token")
+			return
+		}
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toTokenResponse(tokens))
+}
+
+// Logout godoc
+// @Summary      Log out
+// @Description  Revoke the session of the access token, or of the refresh token in the body
+// @Tags         auth
+// @Accept       json
+// @Param        token  body  LogoutRequest  false  "Refresh token"
+// @Success      204  {string}  string  ""
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if claims := auth.GetClaims(c); claims != nil && claims.SessionID != "" {
+		if err := h.service.Logout(c.Request.Context(), claims.SessionID); err != nil {
+			problem.Error(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+	var req LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		problem.Abort(c, problem.TypeBadRequest, "Missing session access token or refresh token")
+		return
+	}
+	if err := h.service.LogoutRefreshToken(c.Request.Context(), req.RefreshToken); err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			problem.Abort(c, problem.TypeUnauthorized, "Invalid refresh token")
+			return
+		}
+		problem.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func toTokenResponse(t *Tokens) TokenResponse {
+	return TokenResponse{
+		AccessToken:  t.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(t.ExpiresIn.Seconds()),
+		RefreshToken: t.RefreshToken,
+	}
+}
@@ -0,0 +1,20 @@
+package users
+
+const (
+	RoleEmployer  = "employer"
+	RoleCandidate = "candidate"
+)
+
+type User struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	Email        string `gorm:"size:255;uniqueIndex" json:"email"`
+	PasswordHash string `gorm:"size:60" json:"-"`
+	Name         string `gorm:"size:255" json:"name"`
+	Role         string `gorm:"size:32" json:"role"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
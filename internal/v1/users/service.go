@@ -0,0 +1,232 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const errDuplicateEntry = 1062
+
+var (
+	ErrEmailTaken          = &apperr.Error{Kind: apperr.ErrConflict, Message: "Email is already registered"}
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+// TooManyAttemptsError is returned by Login while the account or client IP
+// is throttled.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// Service manages accounts and their sessions. Access tokens are short-lived
+// JWTs carrying the session ID; refresh tokens look like "<session>.<secret>"
+// and are rotated on every use.
+type Service struct {
+	repo     UserRepository
+	sessions *SessionStore
+	throttle *LoginThrottle
+	signer   *auth.Signer
+	// dummyHash keeps logins for unknown emails as slow as real ones.
+	dummyHash []byte
+}
+
+func NewService(repo UserRepository, sessions *SessionStore, throttle *LoginThrottle, signer *auth.Signer) *Service {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+	return &Service{
+		repo:      repo,
+		sessions:  sessions,
+		throttle:  throttle,
+		signer:    signer,
+		dummyHash: dummyHash,
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (s *Service) Register(ctx context.Context, req RegisterRequest) (*User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	user := &User{
+		Email:        normalizeEmail(req.Email),
+		PasswordHash: string(hash),
+		Name:         strings.TrimSpace(req.Name),
+		Role:         req.Role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *Service) Login(ctx context.Context, email, password, ip, userAgent string) (*User, *Tokens, error) {
+	email = normalizeEmail(email)
+	wait, err := s.throttle.Check(ctx, email, ip)
+	if err != nil {
+		return nil, nil, err
+	}
+	if wait > 0 {
+		return nil, nil, &TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+	hash := s.dummyHash
+	if user != nil {
+		hash = []byte(user.PasswordHash)
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || user == nil {
+		if err := s.throttle.RecordFailure(ctx, email, ip); err != nil {
+			slog.WarnContext(ctx, "users: failed to record login failure", "email", email, "error", err)
+		}
+		return nil, nil, ErrInvalidCredentials
+	}
+	if err := s.throttle.Reset(ctx, email); err != nil {
+		slog.WarnContext(ctx, "users: failed to reset login throttle", "email", email, "error", err)
+	}
+
+	session := &Session{
+		ID:        randomHex(16),
+		UserID:    user.ID,
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: time.Now().Unix(),
+	}
+	secret := randomHex(32)
+	if err := s.sessions.Create(ctx, session, hashSecret(secret)); err != nil {
+		return nil, nil, err
+	}
+	tokens, err := s.issue(user, session.ID, secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// Refresh rotates the refresh token and issues a new access token. Presenting
+// an already rotated refresh token revokes the whole session, since it means
+// the token was copied.
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	session, err := s.sessions.Get(ctx, sessionID)
+	if db.IsNil(err) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	newSecret := randomHex(32)
+	result, err := s.sessions.Rotate(ctx, sessionID, hashSecret(secret), hashSecret(newSecret))
+	if err != nil {
+		return nil, err
+	}
+	switch result {
+	case rotateReused:
+		slog.WarnContext(ctx, "users: refresh token reuse detected, session revoked", "session_id", sessionID, "user_id", session.UserID)
+		return nil, ErrInvalidRefreshToken
+	case rotateMissing:
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.GetByID(ctx, session.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.sessions.Revoke(ctx, sessionID)
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(user, sessionID, newSecret)
+}
+
+func (s *Service) Logout(ctx context.Context, sessionID string) error {
+	return s.sessions.Revoke(ctx, sessionID)
+}
+
+// LogoutRefreshToken revokes the session a refresh token belongs to. The
+// check goes through Rotate, so a stale token also revokes the session, as
+// it would on refresh.
+func (s *Service) LogoutRefreshToken(ctx context.Context, refreshToken string) error {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return ErrInvalidRefreshToken
+	}
+	hash := hashSecret(secret)
+	result, err := s.sessions.Rotate(ctx, sessionID, hash, hash)
+	if err != nil {
+		return err
+	}
+	if result != rotateOK {
+		return ErrInvalidRefreshToken
+	}
+	return s.sessions.Revoke(ctx, sessionID)
+}
+
+func (s *Service) issue(user *User, sessionID, secret string) (*Tokens, error) {
+	claims := auth.Claims{
+		Roles:     []string{user.Role},
+		SessionID: sessionID,
+	}
+	claims.Subject = strconv.FormatUint(uint64(user.ID), 10)
+	access, err := s.signer.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:  access,
+		RefreshToken: sessionID + "." + secret,
+		ExpiresIn:    s.signer.TTL(),
+	}, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,102 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"github.com/redis/go-redis/v9"
+)
+
+// Session is a login session. The refresh token hash is stored under a
+// separate key so it can be rotated atomically.
+type Session struct {
+	ID        string `json:"id"`
+	UserID    uint   `json:"user_id"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type rotateResult int
+
+const (
+	rotateMissing rotateResult = 0
+	rotateOK      rotateResult = 1
+	// rotateReused means a refresh token that was already rotated away was
+	// presented again; the session is revoked.
+	rotateReused rotateResult = -1
+)
+
+var createSessionScript = redis.NewScript(`
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+var rotateSessionScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[2])
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1], KEYS[2])
+	return -1
+end
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// SessionStore keeps sessions in Redis. Sessions expire after ttl without a
+// refresh; refreshing extends them.
+type SessionStore struct {
+	redis *db.RedisClient
+	ttl   time.Duration
+}
+
+func NewSessionStore(redis *db.RedisClient, ttl time.Duration) *SessionStore {
+	return &SessionStore{redis: redis, ttl: ttl}
+}
+
+func sessionKey(id string) string {
+	return fmt.Sprintf("session:%s", id)
+}
+
+func refreshKey(id string) string {
+	return fmt.Sprintf("session:%s:refresh", id)
+}
+
+func (s *SessionStore) Create(ctx context.Context, session *Session, refreshHash string) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	keys := []string{sessionKey(session.ID), refreshKey(session.ID)}
+	return s.redis.Eval(ctx, createSessionScript, keys, data, refreshHash, s.ttl.Milliseconds()).Err()
+}
+
+func (s *SessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	var session Session
+	if err := s.redis.Get(ctx, sessionKey(id), &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *SessionStore) Rotate(ctx context.Context, id, oldHash, newHash string) (rotateResult, error) {
+	keys := []string{sessionKey(id), refreshKey(id)}
+	n, err := s.redis.Eval(ctx, rotateSessionScript, keys, oldHash, newHash, s.ttl.Milliseconds()).Int64()
+	return rotateResult(n), err
+}
+
+func (s *SessionStore) Revoke(ctx context.Context, id string) error {
+	return s.redis.Del(ctx, sessionKey(id), refreshKey(id))
+}
+
+// SessionActive implements auth.SessionValidator.
+func (s *SessionStore) SessionActive(ctx context.Context, id string) (bool, error) {
+	return s.redis.Exists(ctx, sessionKey(id))
+}
@@ -0,0 +1,88 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/db"
+	"github.com/redis/go-redis/v9"
+)
+
+// checkThrottleScript returns the milliseconds until the caller may try
+// again, or 0 when neither counter has reached its limit.
+var checkThrottleScript = redis.NewScript(`
+local wait = 0
+for i, key in ipairs(KEYS) do
+	local count = tonumber(redis.call('GET', key) or '0')
+	if count >= tonumber(ARGV[i]) then
+		local ttl = redis.call('PTTL', key)
+		if ttl > wait then
+			wait = ttl
+		end
+	end
+end
+return wait
+`)
+
+var recordFailureScript = redis.NewScript(`
+for _, key in ipairs(KEYS) do
+	if redis.call('INCR', key) == 1 then
+		redis.call('PEXPIRE', key, ARGV[1])
+	end
+end
+return 1
+`)
+
+// LoginThrottle counts failed logins per account and per client IP in fixed
+// windows. Once either count reaches its limit, further attempts are
+// refused until the window ends. The account counter is what stops
+// guessing a password: it does not depend on where attempts come from.
+// The IP counter only slows down one client trying many accounts.
+type LoginThrottle struct {
+	redis         *db.RedisClient
+	maxPerAccount int
+	maxPerIP      int
+	window        time.Duration
+}
+
+func NewLoginThrottle(redis *db.RedisClient, maxPerAccount, maxPerIP int, window time.Duration) *LoginThrottle {
+	return &LoginThrottle{redis: redis, maxPerAccount: maxPerAccount, maxPerIP: maxPerIP, window: window}
+}
+
+func accountThrottleKey(email string) string {
+	return fmt.Sprintf("login:fail:account:%s", email)
+}
+
+// ipThrottleKey counts IPv6 clients per /64, the smallest block usually
+// assigned to one client, so that rotating addresses within it does not
+// reset the count.
+func ipThrottleKey(ip string) string {
+	if addr, err := netip.ParseAddr(ip); err == nil && addr.Unmap().Is6() {
+		prefix, _ := addr.Prefix(64)
+		ip = prefix.String()
+	}
+	return fmt.Sprintf("login:fail:ip:%s", ip)
+}
+
+// Check returns how long the caller must wait before trying again, or zero.
+func (t *LoginThrottle) Check(ctx context.Context, email, ip string) (time.Duration, error) {
+	keys := []string{accountThrottleKey(email), ipThrottleKey(ip)}
+	ms, err := t.redis.Eval(ctx, checkThrottleScript, keys, t.maxPerAccount, t.maxPerIP).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, ip string) error {
+	keys := []string{accountThrottleKey(email), ipThrottleKey(ip)}
+	return t.redis.Eval(ctx, recordFailureScript, keys, t.window.Milliseconds()).Err()
+}
+
+// Reset clears the account counter after a successful login. The IP counter
+// is left alone so one valid account cannot be used to reset it.
+func (t *LoginThrottle) Reset(ctx context.Context, email string) error {
+	return t.redis.Del(ctx, accountThrottleKey(email))
+}
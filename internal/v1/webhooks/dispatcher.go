@@ -0,0 +1,211 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/metrics"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/jobs"
+)
+
+const (
+	MaxAttempts  = 8
+	pollInterval = 2 * time.Second
+	batchSize    = 50
+	baseBackoff  = 30 * time.Second
+	maxBackoff   = 6 * time.Hour
+	sendTimeout  = 10 * time.Second
+	// claimLease outlasts sending a whole batch to endpoints that all time
+	// out, so a claimed delivery is not sent twice.
+	claimLease = batchSize*sendTimeout + time.Minute
+)
+
+type payload struct {
+	ID         string         `json:"id"`
+	Type       jobs.EventType `json:"type"`
+	OccurredAt int64          `json:"occurred_at"`
+	Data       jobs.Job       `json:"data"`
+}
+
+// Dispatcher queues a delivery per subscriber for every job event and sends
+// due deliveries from a background loop, retrying failures with exponential
+// backoff until MaxAttempts is reached.
+type Dispatcher struct {
+	repo   WebhookRepository
+	client *http.Client
+}
+
+func NewDispatcher(repo WebhookRepository) *Dispatcher {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect on our behalf, past publicDialer.
+	transport.Proxy = nil
+	transport.DialContext = publicDialer(sendTimeout).DialContext
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: sendTimeout, Transport: transport},
+	}
+}
+
+// Publish queues one delivery per subscriber. Deliveries are unique per
+// subscription and event ID, so publishing the same event twice is a no-op.
+func (d *Dispatcher) Publish(ctx context.Context, event jobs.JobEvent) error {
+	subs, err := d.repo.SubscribersFor(ctx, string(event.Type))
+	if err != nil {
+		return fmt.Errorf("webhooks: load subscribers for %s: %w", event.Type, err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(payload{ID: event.ID, Type: event.Type, OccurredAt: event.OccurredAt, Data: event.Job})
+	if err != nil {
+		return fmt.Errorf("webhooks: encode %s: %w", event.Type, err)
+	}
+	now := time.Now().Unix()
+	deliveries := make([]Delivery, len(subs))
+	for i, sub := range subs {
+		deliveries[i] = Delivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      string(event.Type),
+			Payload:        string(body),
+			Status:         DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	}
+	if err := d.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("webhooks: queue %s deliveries: %w", event.Type, err)
+	}
+	return nil
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.deliverDue(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	now := time.Now()
+	due, err := d.repo.ClaimDue(ctx, now.Unix(), now.Add(claimLease).Unix(), batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhooks: failed to load due deliveries", "error", err)
+		return
+	}
+	subs := make(map[uint]*Subscription)
+	for i := range due {
+		if ctx.Err() != nil {
+			// Shutting down; the rest are sent once their claim expires.
+			return
+		}
+		delivery := &due[i]
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil {
+				// The delivery is retried once its claim expires.
+				slog.ErrorContext(ctx, "webhooks: failed to load subscription", "subscription_id", delivery.SubscriptionID, "error", err)
+				continue
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+		d.attempt(ctx, sub, delivery)
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, sub *Subscription, delivery *Delivery) {
+	started := time.Now()
+	statusCode, err := d.send(ctx, sub, delivery)
+	if err != nil && ctx.Err() != nil {
+		// Interrupted by shutdown, not the endpoint's fault: the
+		// delivery is sent again once its claim expires, without counting
+		// an attempt.
+		return
+	}
+
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now().Unix()
+	attempt := DeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		DurationMs: time.Since(started).Milliseconds(),
+		CreatedAt:  delivery.UpdatedAt,
+	}
+	switch {
+	case err == nil:
+		delivery.Status = DeliverySucceeded
+		delivery.LastError = ""
+		metrics.WebhookDeliveries.WithLabelValues("succeeded").Inc()
+	case delivery.Attempts >= MaxAttempts:
+		delivery.Status = DeliveryDead
+		delivery.LastError = err.Error()
+		attempt.Error = err.Error()
+		metrics.WebhookDeliveries.WithLabelValues("dead").Inc()
+		slog.WarnContext(ctx, "webhooks: delivery moved to dead letters", "delivery_id", delivery.ID, "url", sub.URL, "attempts", delivery.Attempts, "error", err)
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(Backoff(delivery.Attempts)).Unix()
+		attempt.Error = err.Error()
+		metrics.WebhookDeliveries.WithLabelValues("retry").Inc()
+	}
+	if err := d.repo.RecordAttempt(ctx, delivery, &attempt); err != nil {
+		slog.ErrorContext(ctx, "webhooks: failed to record attempt", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *Subscription, delivery *Delivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "job-posting-service-webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the delay before the next attempt after the given number
+// of failed attempts: 30s doubled per attempt, capped at 6h, with up to 20%
+// jitter so failing endpoints are not retried in lockstep.
+func Backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
@@ -0,0 +1,23 @@
+package webhooks
+
+type CreateSubscriptionRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=job.created job.updated job.deleted job.status_changed"`
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=128"`
+}
+
+type SubscriptionResponse struct {
+	ID        uint     `json:"id"`
+	OwnerID   string   `json:"owner_id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	CreatedAt int64    `json:"created_at"`
+	// Secret is only returned when the subscription is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+type DeliveryResponse struct {
+	Delivery
+	AttemptLog []DeliveryAttempt `json:"attempt_log,omitempty"`
+}
@@ -0,0 +1,318 @@
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/auth"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/problem"
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/v1/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	repo  WebhookRepository
+	pages pagination.Limits
+}
+
+func NewWebhookHandler(repo WebhookRepository, pages pagination.Limits) *WebhookHandler {
+	return &WebhookHandler{repo: repo, pages: pages}
+}
+
+// CreateSubscription godoc
+// @Summary      Register a webhook
+// @Description  Subscribe a public http(s) URL to job lifecycle events. The signing secret is only returned in this response.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        subscription  body  CreateSubscriptionRequest  true  "Subscription"
+// @Success      201  {object}  SubscriptionResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	if err := checkTarget(c.Request.Context(), req.URL); err != nil {
+		problem.Error(c, apperr.InvalidFields(apperr.FieldError{Field: "url", Rule: "public_url", Message: err.Error()}))
+		return
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = newSecret()
+	}
+	sub := Subscription{
+		OwnerID:   ownerID(c),
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    strings.Join(req.Events, ","),
+		Active:    true,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := h.repo.CreateSubscription(c.Request.Context(), &sub); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	resp := toSubscriptionResponse(&sub)
+	resp.Secret = sub.Secret
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListSubscriptions godoc
+// @Summary      List webhooks
+// @Description  List the caller's webhooks, or every webhook for admins
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subs, err := h.repo.ListSubscriptions(c.Request.Context(), ownerFilter(c))
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	responses := make([]SubscriptionResponse, len(subs))
+	for i := range subs {
+		responses[i] = toSubscriptionResponse(&subs[i])
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": responses})
+}
+
+// GetSubscription godoc
+// @Summary      Get a webhook
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path  int  true  "Webhook ID"
+// @Success      200  {object}  SubscriptionResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	id, ok := parseID(c, "Invalid webhook id")
+	if !ok {
+		return
+	}
+	sub, ok := h.loadSubscription(c, id)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, toSubscriptionResponse(sub))
+}
+
+// DeleteSubscription godoc
+// @Summary      Delete a webhook
+// @Description  Deactivate a webhook; pending deliveries are moved to the dead-letter list
+// @Tags         webhooks
+// @Param        id   path  int  true  "Webhook ID"
+// @Success      204  {string}  string  ""
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	id, ok := parseID(c, "Invalid webhook id")
+	if !ok {
+		return
+	}
+	sub, ok := h.loadSubscription(c, id)
+	if !ok {
+		return
+	}
+	if err := h.repo.DeleteSubscription(c.Request.Context(), sub.ID); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries godoc
+// @Summary      Webhook delivery log
+// @Description  Get the deliveries of a webhook, newest first
+// @Tags         webhooks
+// @Produce      json
+// @Param        id     path   int  true   "Webhook ID"
+// @Param        page   query  int  false  "Page number"
+// @Param        limit  query  int  false  "Page size"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseID(c, "Invalid webhook id")
+	if !ok {
+		return
+	}
+	sub, ok := h.loadSubscription(c, id)
+	if !ok {
+		return
+	}
+	page, limit := h.pages.Parse(c)
+	deliveries, total, err := h.repo.ListDeliveries(c.Request.Context(), sub.ID, (page-1)*limit, limit)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries, "total": total, "page": page, "limit": limit})
+}
+
+// ListDeadLetters godoc
+// @Summary      Webhook dead letters
+// @Description  Get deliveries of the caller's webhooks (every webhook for admins) that were given up on after repeated failures
+// @Tags         webhooks
+// @Produce      json
+// @Param        page   query  int  false  "Page number"
+// @Param        limit  query  int  false  "Page size"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	page, limit := h.pages.Parse(c)
+	deliveries, total, err := h.repo.ListDeadLetters(c.Request.Context(), ownerFilter(c), (page-1)*limit, limit)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries, "total": total, "page": page, "limit": limit})
+}
+
+// GetDelivery godoc
+// @Summary      Get a webhook delivery
+// @Description  Get a delivery with the log of every attempt
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path  int  true  "Delivery ID"
+// @Success      200  {object}  DeliveryResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks/deliveries/{id} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, ok := parseID(c, "Invalid delivery id")
+	if !ok {
+		return
+	}
+	delivery, ok := h.loadDelivery(c, id)
+	if !ok {
+		return
+	}
+	attempts, err := h.repo.ListAttempts(c.Request.Context(), delivery.ID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, DeliveryResponse{Delivery: *delivery, AttemptLog: attempts})
+}
+
+// RetryDelivery godoc
+// @Summary      Retry a dead delivery
+// @Description  Move a dead-lettered delivery back to the queue
+// @Tags         webhooks
+// @Param        id   path  int  true  "Delivery ID"
+// @Success      202  {string}  string  ""
+// @Failure      400  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /webhooks/deliveries/{id}/retry [post]
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	id, ok := parseID(c, "Invalid delivery id")
+	if !ok {
+		return
+	}
+	delivery, ok := h.loadDelivery(c, id)
+	if !ok {
+		return
+	}
+	if err := h.repo.Requeue(c.Request.Context(), delivery.ID, time.Now().Unix()); err != nil {
+		problem.Error(c, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// loadSubscription returns the subscription if the caller owns it or is an
+// admin, and aborts the request otherwise. With authentication disabled
+// there is no principal and every request passes.
+func (h *WebhookHandler) loadSubscription(c *gin.Context, id uint) (*Subscription, bool) {
+	sub, err := h.repo.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		problem.Error(c, err)
+		return nil, false
+	}
+	if principal := auth.GetPrincipal(c); principal != nil && !principal.HasRole("admin") && !principal.Owns(sub.OwnerID) {
+		problem.Abort(c, problem.TypeForbidden, "Not allowed to manage this webhook")
+		return nil, false
+	}
+	return sub, true
+}
+
+// loadDelivery returns the delivery if the caller may manage its
+// subscription, and aborts the request otherwise.
+func (h *WebhookHandler) loadDelivery(c *gin.Context, id uint) (*Delivery, bool) {
+	delivery, err := h.repo.GetDelivery(c.Request.Context(), id)
+	if err != nil {
+		problem.Error(c, err)
+		return nil, false
+	}
+	if _, ok := h.loadSubscription(c, delivery.SubscriptionID); !ok {
+		return nil, false
+	}
+	return delivery, true
+}
+
+func ownerID(c *gin.Context) string {
+	if principal := auth.GetPrincipal(c); principal != nil {
+		return principal.ID
+	}
+	return ""
+}
+
+// ownerFilter is the owner whose webhooks the caller may list: its own, or
+// every owner ("") for admins and when authentication is disabled.
+func ownerFilter(c *gin.Context) string {
+	if principal := auth.GetPrincipal(c); principal != nil && !principal.HasRole("admin") {
+		return principal.ID
+	}
+	return ""
+}
+
+func parseID(c *gin.Context, message string) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		problem.Abort(c, problem.TypeBadRequest, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func toSubscriptionResponse(sub *Subscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		ID:        sub.ID,
+		OwnerID:   sub.OwnerID,
+		URL:       sub.URL,
+		Events:    strings.Split(sub.Events, ","),
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package webhooks
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+type Subscription struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	OwnerID   string `gorm:"size:255;index" json:"owner_id"`
+	URL       string `gorm:"size:2048" json:"url"`
+	Secret    string `gorm:"size:128" json:"-"`
+	Events    string `gorm:"size:255" json:"events"`
+	Active    bool   `gorm:"index" json:"active"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Delivery is one event queued for one subscription. Deliveries that keep
+// failing end up with status "dead" and form the dead-letter list.
+type Delivery struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint   `gorm:"uniqueIndex:idx_webhook_delivery_event" json:"subscription_id"`
+	EventID        string `gorm:"size:64;uniqueIndex:idx_webhook_delivery_event" json:"event_id"`
+	EventType      string `gorm:"size:64" json:"event_type"`
+	Payload        string `gorm:"type:text" json:"payload"`
+	Status         string `gorm:"size:16;index:idx_webhook_delivery_due" json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  int64  `gorm:"index:idx_webhook_delivery_due" json:"next_attempt_at"`
+	LastError      string `gorm:"type:text" json:"last_error"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type DeliveryAttempt struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	DeliveryID uint   `gorm:"index" json:"delivery_id"`
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"status_code"`
+	Error      string `gorm:"type:text" json:"error"`
+	DurationMs int64  `json:"duration_ms"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+func (DeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
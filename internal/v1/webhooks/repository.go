@@ -0,0 +1,198 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/AtaAksoy/se4458-go-job-posting-service/internal/apperr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	GetSubscription(ctx context.Context, id uint) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, ownerID string) ([]Subscription, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	SubscribersFor(ctx context.Context, eventType string) ([]Subscription, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []Delivery) error
+	GetDelivery(ctx context.Context, id uint) (*Delivery, error)
+	ClaimDue(ctx context.Context, now, leaseUntil int64, limit int) ([]Delivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID uint, offset, limit int) ([]Delivery, int64, error)
+	ListDeadLetters(ctx context.Context, ownerID string, offset, limit int) ([]Delivery, int64, error)
+	ListAttempts(ctx context.Context, deliveryID uint) ([]DeliveryAttempt, error)
+	RecordAttempt(ctx context.Context, delivery *Delivery, attempt *DeliveryAttempt) error
+	Requeue(ctx context.Context, id uint, now int64) error
+}
+
+type GormWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &GormWebhookRepository{db: db}
+}
+
+func (r *GormWebhookRepository) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+func (r *GormWebhookRepository) GetSubscription(ctx context.Context, id uint) (*Subscription, error) {
+	var sub Subscription
+	if err := r.db.WithContext(ctx).First(&sub, id).Error; err != nil {
+		return nil, apperr.FromDB(err, "Webhook")
+	}
+	return &sub, nil
+}
+
+// ListSubscriptions lists the subscriptions of ownerID, or all of them when
+// ownerID is empty.
+func (r *GormWebhookRepository) ListSubscriptions(ctx context.Context, ownerID string) ([]Subscription, error) {
+	var subs []Subscription
+	q := r.db.WithContext(ctx)
+	if ownerID != "" {
+		q = q.Where("owner_id = ?", ownerID)
+	}
+	if err := q.Order("created_at desc").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// DeleteSubscription deactivates the subscription instead of removing it so
+// its delivery log stays inspectable.
+func (r *GormWebhookRepository) DeleteSubscription(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Subscription{}).Where("id = ?", id).Update("active", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Delivery{}).
+			Where("subscription_id = ? AND status = ?", id, DeliveryPending).
+			Updates(map[string]interface{}{"status": DeliveryDead, "last_error": "subscription deleted"}).Error
+	})
+}
+
+func (r *GormWebhookRepository) SubscribersFor(ctx context.Context, eventType string) ([]Subscription, error) {
+	var subs []Subscription
+	err := r.db.WithContext(ctx).
+		Where("active = ? AND FIND_IN_SET(?, events) > 0", true, eventType).
+		Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *GormWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+func (r *GormWebhookRepository) GetDelivery(ctx context.Context, id uint) (*Delivery, error) {
+	var delivery Delivery
+	if err := r.db.WithContext(ctx).First(&delivery, id).Error; err != nil {
+		return nil, apperr.FromDB(err, "Delivery")
+	}
+	return &delivery, nil
+}
+
+// ClaimDue returns up to limit due deliveries and moves their next attempt
+// to leaseUntil, so that other replicas skip them while they are sent. Rows
+// locked by another replica's claim are skipped rather than waited for. A
+// delivery whose sender dies before recording the attempt becomes due again
+// at leaseUntil.
+func (r *GormWebhookRepository) ClaimDue(ctx context.Context, now, leaseUntil int64, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Order("next_attempt_at asc, id asc").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uint, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&Delivery{}).Where("id IN ?", ids).Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *GormWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uint, offset, limit int) ([]Delivery, int64, error) {
+	return r.listDeliveries(ctx, r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID), offset, limit)
+}
+
+// ListDeadLetters lists the dead deliveries of ownerID's subscriptions, or
+// of all subscriptions when ownerID is empty.
+func (r *GormWebhookRepository) ListDeadLetters(ctx context.Context, ownerID string, offset, limit int) ([]Delivery, int64, error) {
+	q := r.db.WithContext(ctx).Where("status = ?", DeliveryDead)
+	if ownerID != "" {
+		q = q.Where("subscription_id IN (?)", r.db.WithContext(ctx).Model(&Subscription{}).Select("id").Where("owner_id = ?", ownerID))
+	}
+	return r.listDeliveries(ctx, q, offset, limit)
+}
+
+func (r *GormWebhookRepository) listDeliveries(ctx context.Context, q *gorm.DB, offset, limit int) ([]Delivery, int64, error) {
+	var deliveries []Delivery
+	var total int64
+	if err := q.Model(&Delivery{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("id desc").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *GormWebhookRepository) ListAttempts(ctx context.Context, deliveryID uint) ([]DeliveryAttempt, error) {
+	var attempts []DeliveryAttempt
+	err := r.db.WithContext(ctx).Where("delivery_id = ?", deliveryID).Order("attempt asc").Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// RecordAttempt stores the attempt log entry together with the delivery's
+// new status, attempt count and next attempt time.
+func (r *GormWebhookRepository) RecordAttempt(ctx context.Context, delivery *Delivery, attempt *DeliveryAttempt) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Delivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_error":      delivery.LastError,
+			"updated_at":      delivery.UpdatedAt,
+		}).Error
+	})
+}
+
+// Requeue moves a dead delivery back to pending with a fresh attempt budget.
+func (r *GormWebhookRepository) Requeue(ctx context.Context, id uint, now int64) error {
+	res := r.db.WithContext(ctx).Model(&Delivery{}).
+		Where("id = ? AND status = ?", id, DeliveryDead).
+		Updates(map[string]interface{}{
+			"status":          DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return apperr.NotFound("Dead delivery not found")
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package jobfeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Handler processes one event. Returning an error leaves the entry pending
+// so it is redelivered once it has been idle for Options.ClaimIdle.
+type Handler func(ctx context.Context, event Event) error
+
+type Options struct {
+	// Count is the maximum number of entries read per call. Default 16.
+	Count int64
+	// Block is how long a read waits for new entries. Default 5s.
+	Block time.Duration
+	// ClaimIdle is how long an entry must stay unacknowledged before this
+	// consumer takes it over from a crashed or failing consumer. Default 1m.
+	ClaimIdle time.Duration
+	// OnError is called for handler errors and undecodable entries; entries
+	// that cannot be decoded are acknowledged so they do not block the group.
+	OnError func(id string, err error)
+}
+
+// Consumer reads the feed as one member of a Redis consumer group and
+// acknowledges each entry after its handler succeeds.
+type Consumer struct {
+	client redis.UniversalClient
+	stream string
+	group  string
+	name   string
+	opts   Options
+}
+
+func NewConsumer(client redis.UniversalClient, stream, group, name string, opts Options) *Consumer {
+	if opts.Count <= 0 {
+		opts.Count = 16
+	}
+	if opts.Block <= 0 {
+		opts.Block = 5 * time.Second
+	}
+	if opts.ClaimIdle <= 0 {
+		opts.ClaimIdle = time.Minute
+	}
+	return &Consumer{client: client, stream: stream, group: group, name: name, opts: opts}
+}
+
+// EnsureGroup creates the consumer group (and the stream) if needed. A new
+// group starts at the end of the stream; use start "0" to replay history.
+func (c *Consumer) EnsureGroup(ctx context.Context, start string) error {
+	if start == "" {
+		start = "$"
+	}
+	err := c.client.XGroupCreateMkStream(ctx, c.stream, c.group, start).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// Run consumes the feed until ctx is cancelled. Entries left pending by
+// other consumers for longer than ClaimIdle are claimed and retried before
+// new entries are read.
+func (c *Consumer) Run(ctx context.Context, handler Handler) error {
+	if err := c.EnsureGroup(ctx, "$"); err != nil {
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.claimStale(ctx, handler); err != nil {
+			return err
+		}
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.name,
+			Streams:  []string{c.stream, ">"},
+			Count:    c.opts.Count,
+			Block:    c.opts.Block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				c.process(ctx, msg, handler)
+			}
+		}
+	}
+}
+
+func (c *Consumer) claimStale(ctx context.Context, handler Handler) error {
+	start := "0-0"
+	for {
+		msgs, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   c.stream,
+			Group:    c.group,
+			Consumer: c.name,
+			MinIdle:  c.opts.ClaimIdle,
+			Start:    start,
+			Count:    c.opts.Count,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			c.process(ctx, msg, handler)
+		}
+		if next == "0-0" || len(msgs) == 0 {
+			return nil
+		}
+		start = next
+	}
+}
+
+func (c *Consumer) process(ctx context.Context, msg redis.XMessage, handler Handler) {
+	event, err := decode(msg)
+	if err != nil {
+		c.reportError(msg.ID, err)
+		c.client.XAck(ctx, c.stream, c.group, msg.ID)
+		return
+	}
+	if err := handler(ctx, event); err != nil {
+		c.reportError(msg.ID, err)
+		return
+	}
+	if err := c.client.XAck(ctx, c.stream, c.group, msg.ID).Err(); err != nil {
+		c.reportError(msg.ID, err)
+	}
+}
+
+func (c *Consumer) reportError(id string, err error) {
+	if c.opts.OnError != nil {
+		c.opts.OnError(id, err)
+	}
+}
+
+func decode(msg redis.XMessage) (Event, error) {
+	var event Event
+	raw, ok := msg.Values[FieldEvent].(string)
+	if !ok {
+		return event, fmt.Errorf("entry %s has no %q field", msg.ID, FieldEvent)
+	}
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		return event, fmt.Errorf("entry %s: %w", msg.ID, err)
+	}
+	return event, nil
+}
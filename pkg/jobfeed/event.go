@@ -0,0 +1,66 @@
+// Package jobfeed reads the job change feed that the job posting service
+// publishes to a Redis Stream. Every stream entry holds one CloudEvents 1.0
+// JSON envelope in its "event" field.
+package jobfeed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	SpecVersion     = "1.0"
+	Source          = "/se4458-go-job-posting-service/jobs"
+	DataContentType = "application/json"
+
+	// FieldEvent is the stream entry field holding the JSON envelope.
+	FieldEvent = "event"
+	// FieldType duplicates the event type so consumers can filter entries
+	// without decoding them.
+	FieldType = "type"
+
+	TypeJobCreated       = "com.se4458.job.created"
+	TypeJobUpdated       = "com.se4458.job.updated"
+	TypeJobDeleted       = "com.se4458.job.deleted"
+	TypeJobStatusChanged = "com.se4458.job.status_changed"
+)
+
+// Event is a CloudEvents 1.0 envelope in structured JSON mode.
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// Job is the data carried by job events.
+type Job struct {
+	ID          uint   `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Company     string `json:"company"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Status      bool   `json:"status"`
+	CreatedAt   int64  `json:"created_at"`
+	// Lang is empty for jobs in the service's default language.
+	Lang         string        `json:"lang"`
+	Translations []Translation `json:"translations"`
+}
+
+// Translation is the title and description of a job in another language.
+type Translation struct {
+	Lang        string `json:"lang"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (e Event) Job() (Job, error) {
+	var job Job
+	err := json.Unmarshal(e.Data, &job)
+	return job, err
+}